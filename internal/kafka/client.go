@@ -9,9 +9,27 @@ import (
 type KafkaClient interface {
 	Connect(ctx context.Context) error
 	Close()
+	DescribeCluster(ctx context.Context) (models.ClusterInfo, error)
 	ListTopics(ctx context.Context) ([]models.Topic, error)
 	GetTopicPartitions(ctx context.Context, topicName string) ([]models.Partition, error)
 	CreateTopic(ctx context.Context, config models.TopicConfig) error
+	DeleteTopics(ctx context.Context, topics ...string) error
+	UpdatePartitions(ctx context.Context, topic string, total int) error
+	PreviewTruncate(ctx context.Context, spec models.TruncateSpec) ([]models.TruncatePreview, error)
+	DeleteRecords(ctx context.Context, previews []models.TruncatePreview) error
+	ListConsumerGroups(ctx context.Context) ([]models.ConsumerGroup, error)
+	GetConsumerGroupOffsets(ctx context.Context, group string) ([]models.ConsumerGroupOffset, error)
+	GetConsumerGroupMembers(ctx context.Context, group string) ([]models.ConsumerGroupMember, error)
+	PreviewOffsetReset(ctx context.Context, spec models.OffsetResetSpec) ([]models.OffsetResetPreview, error)
+	ResetConsumerGroupOffsets(ctx context.Context, group string, previews []models.OffsetResetPreview) error
+	DeleteConsumerGroup(ctx context.Context, group string) error
+	DeleteConsumerGroupOffsets(ctx context.Context, group, topic string) error
+	FetchMessages(ctx context.Context, query models.MessageQuery) (models.MessagePage, error)
+	TailMessages(ctx context.Context, topic string, onMessages func([]models.Message)) error
+	GetTopicConfigs(ctx context.Context, topic string) ([]models.ConfigEntry, error)
+	AlterTopicConfigs(ctx context.Context, topic string, alterations []models.ConfigAlteration) error
+	ValidateTopicConfigs(ctx context.Context, topic string, alterations []models.ConfigAlteration) error
+	ProduceMessages(ctx context.Context, records []models.ProduceRecord) ([]models.ProduceResult, error)
 }
 
 type ClientFactory interface {
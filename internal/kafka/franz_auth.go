@@ -0,0 +1,161 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/oauth"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+const (
+	tokenRequestTimeout = 10 * time.Second
+	// tokenExpiryMargin refreshes a token slightly before the endpoint says it
+	// expires, so a connection never authenticates with a stale one
+	tokenExpiryMargin = 30 * time.Second
+)
+
+// saslMechanism builds the SASL mechanism selected in config
+func saslMechanism(config models.BrokerConfig) (sasl.Mechanism, error) {
+	switch config.SASLMechanism {
+	case models.SASLPlain:
+		if config.Username == "" {
+			return nil, errors.New("username is required for SASL/PLAIN")
+		}
+		return plain.Auth{
+			User: config.Username,
+			Pass: config.Password,
+		}.AsMechanism(), nil
+	case models.SASLSCRAMSHA256, models.SASLSCRAMSHA512:
+		if config.Username == "" || config.Password == "" {
+			return nil, fmt.Errorf("username and password are required for SASL/%s", config.SASLMechanism)
+		}
+		auth := scram.Auth{
+			User: config.Username,
+			Pass: config.Password,
+		}
+		if config.SASLMechanism == models.SASLSCRAMSHA512 {
+			return auth.AsSha512Mechanism(), nil
+		}
+		return auth.AsSha256Mechanism(), nil
+	case models.SASLOAuthBearer:
+		if config.UsesOAuthClientCredentials() {
+			source := &oauthTokenSource{
+				tokenURL:     config.OAuthTokenURL,
+				clientID:     config.Username,
+				clientSecret: config.Password,
+				scope:        config.OAuthScope,
+				httpClient:   &http.Client{Timeout: tokenRequestTimeout},
+			}
+			return oauth.Oauth(source.auth), nil
+		}
+		if config.Password == "" {
+			return nil, errors.New("token is required for SASL/OAUTHBEARER")
+		}
+		return oauth.Auth{Token: config.Password}.AsMechanism(), nil
+	}
+	return nil, fmt.Errorf("unsupported SASL mechanism %d", config.SASLMechanism)
+}
+
+// oauthTokenSource fetches OAUTHBEARER tokens with the OAuth 2.0 client
+// credentials grant and reuses them until shortly before they expire
+type oauthTokenSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scope        string
+	httpClient   *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type tokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (s *oauthTokenSource) auth(ctx context.Context) (oauth.Auth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Before(s.expiry)) {
+		return oauth.Auth{Token: s.token}, nil
+	}
+
+	token, expiresIn, err := s.fetch(ctx)
+	if err != nil {
+		return oauth.Auth{}, err
+	}
+
+	s.token = token
+	s.expiry = time.Time{}
+	if expiresIn > 0 {
+		lifetime := time.Duration(expiresIn) * time.Second
+		// short-lived tokens are refreshed halfway instead
+		margin := min(tokenExpiryMargin, lifetime/2)
+		s.expiry = time.Now().Add(lifetime - margin)
+	}
+	return oauth.Auth{Token: token}, nil
+}
+
+func (s *oauthTokenSource) fetch(ctx context.Context) (string, int64, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if s.scope != "" {
+		form.Set("scope", s.scope)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", 0, fmt.Errorf("read token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var tokenErr tokenErrorResponse
+		if json.Unmarshal(body, &tokenErr) == nil && tokenErr.Error != "" {
+			return "", 0, fmt.Errorf("token endpoint returned %s: %s %s", resp.Status, tokenErr.Error, tokenErr.ErrorDescription)
+		}
+		return "", 0, fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", 0, fmt.Errorf("decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", 0, errors.New("token endpoint returned no access_token")
+	}
+	return token.AccessToken, token.ExpiresIn, nil
+}
@@ -2,20 +2,23 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"strconv"
 	"strings"
 
 	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tlsconfig"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
-	"github.com/twmb/franz-go/pkg/sasl/plain"
 )
 
 type franzClient struct {
 	client *kgo.Client
 	admin  *kadm.Client
 	config models.BrokerConfig
+	opts   []kgo.Opt
 }
 
 type franzClientFactory struct{}
@@ -32,11 +35,19 @@ func (f *franzClientFactory) NewClient(config models.BrokerConfig) (KafkaClient,
 
 	opts := []kgo.Opt{kgo.SeedBrokers(seeds...)}
 
-	if config.AuthType == models.AuthSASL && config.Username != "" {
-		mechanism := plain.Auth{
-			User: config.Username,
-			Pass: config.Password,
-		}.AsMechanism()
+	if config.TLSEnabled() {
+		tlsConfig, err := tlsconfig.New(*config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+
+	if config.AuthType == models.AuthSASL {
+		mechanism, err := saslMechanism(config)
+		if err != nil {
+			return nil, err
+		}
 		opts = append(opts, kgo.SASL(mechanism))
 	}
 
@@ -49,6 +60,7 @@ func (f *franzClientFactory) NewClient(config models.BrokerConfig) (KafkaClient,
 		client: client,
 		admin:  kadm.NewClient(client),
 		config: config,
+		opts:   opts,
 	}, nil
 }
 
@@ -74,13 +86,10 @@ func (c *franzClient) ListTopics(ctx context.Context) ([]models.Topic, error) {
 	}
 
 	resourceConfigs, _ := c.admin.DescribeTopicConfigs(ctx, topicNames...)
-	configMap := make(map[string]map[string]string)
+	configMap := make(map[string][]models.ConfigEntry)
 	for _, rc := range resourceConfigs {
-		configMap[rc.Name] = make(map[string]string)
-		for _, cfg := range rc.Configs {
-			if cfg.Value != nil {
-				configMap[rc.Name][cfg.Key] = *cfg.Value
-			}
+		if rc.Err == nil {
+			configMap[rc.Name] = toConfigEntries(rc.Configs)
 		}
 	}
 
@@ -121,9 +130,10 @@ func (c *franzClient) ListTopics(ctx context.Context) ([]models.Topic, error) {
 		}
 
 		cleanupPolicy := "delete"
-		if cfg, ok := configMap[t.Topic]; ok {
-			if val, ok := cfg["cleanup.policy"]; ok {
-				cleanupPolicy = val
+		configs := configMap[t.Topic]
+		for _, cfg := range configs {
+			if cfg.Name == "cleanup.policy" && cfg.Value != "" {
+				cleanupPolicy = cfg.Value
 			}
 		}
 
@@ -136,6 +146,7 @@ func (c *franzClient) ListTopics(ctx context.Context) ([]models.Topic, error) {
 			CleanUpPolicy:  strings.ToUpper(cleanupPolicy),
 			MessageCount:   messageCount,
 			IsInternal:     t.IsInternal,
+			Configs:        configs,
 		})
 	}
 
@@ -219,6 +230,42 @@ func (c *franzClient) CreateTopic(ctx context.Context, config models.TopicConfig
 	return resp.Err
 }
 
+func (c *franzClient) DeleteTopics(ctx context.Context, topics ...string) error {
+	resp, err := c.admin.DeleteTopics(ctx, topics...)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, r := range resp.Sorted() {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("delete topic %q: %w", r.Topic, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UpdatePartitions grows topic to total partitions, letting the cluster
+// choose the replica placement of the new ones
+func (c *franzClient) UpdatePartitions(ctx context.Context, topic string, total int) error {
+	resp, err := c.admin.UpdatePartitions(ctx, total, topic)
+	if err != nil {
+		return err
+	}
+
+	r, err := resp.On(topic, nil)
+	if err != nil {
+		return err
+	}
+	if r.Err != nil {
+		if r.ErrMessage != "" {
+			return fmt.Errorf("add partitions to topic %q: %w: %s", topic, r.Err, r.ErrMessage)
+		}
+		return fmt.Errorf("add partitions to topic %q: %w", topic, r.Err)
+	}
+	return nil
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// DescribeCluster returns the cluster ID, controller and brokers with their
+// partition leadership and replica counts, and the API versions each broker
+// supports
+func (c *franzClient) DescribeCluster(ctx context.Context) (models.ClusterInfo, error) {
+	metadata, err := c.admin.Metadata(ctx)
+	if err != nil {
+		return models.ClusterInfo{}, err
+	}
+
+	leaders := make(map[int32]int)
+	replicas := make(map[int32]int)
+	for _, topic := range metadata.Topics {
+		for _, p := range topic.Partitions {
+			if p.Leader >= 0 {
+				leaders[p.Leader]++
+			}
+			for _, r := range p.Replicas {
+				replicas[r]++
+			}
+		}
+	}
+
+	info := models.ClusterInfo{
+		ClusterID:    metadata.Cluster,
+		ControllerID: metadata.Controller,
+		Brokers:      make([]models.BrokerNode, 0, len(metadata.Brokers)),
+	}
+	index := make(map[int32]int, len(metadata.Brokers))
+	for _, b := range metadata.Brokers {
+		node := models.BrokerNode{
+			ID:       b.NodeID,
+			Host:     b.Host,
+			Port:     b.Port,
+			Leaders:  leaders[b.NodeID],
+			Replicas: replicas[b.NodeID],
+		}
+		if b.Rack != nil {
+			node.Rack = *b.Rack
+		}
+		index[b.NodeID] = len(info.Brokers)
+		info.Brokers = append(info.Brokers, node)
+	}
+
+	versions, err := c.admin.ApiVersions(ctx)
+	if err != nil {
+		return info, err
+	}
+	for _, v := range versions.Sorted() {
+		if v.Err != nil {
+			continue
+		}
+		if i, ok := index[v.NodeID]; ok {
+			info.Brokers[i].Version = v.VersionGuess()
+		}
+		if info.APIVersions == nil || v.NodeID == metadata.Controller {
+			info.Version = v.VersionGuess()
+			info.APIVersions = toAPIVersions(v)
+		}
+	}
+
+	return info, nil
+}
+
+func toAPIVersions(v kadm.BrokerApiVersions) []models.APIVersion {
+	var versions []models.APIVersion
+	v.EachKeySorted(func(key, minVersion, maxVersion int16) {
+		versions = append(versions, models.APIVersion{
+			Key:        key,
+			Name:       kmsg.NameForKey(key),
+			MinVersion: minVersion,
+			MaxVersion: maxVersion,
+		})
+	})
+	return versions
+}
+
+// ExplainConnectError adds a hint about the likely cause of a failed
+// connection, telling DNS, network, TLS and authentication problems apart
+func ExplainConnectError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var dnsErr *net.DNSError
+	var recordErr tls.RecordHeaderError
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return fmt.Errorf("DNS lookup of %s failed, check the bootstrap servers: %w", dnsErr.Name, err)
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return fmt.Errorf("connection refused, check the host and port: %w", err)
+	case errors.As(err, &recordErr):
+		return fmt.Errorf("TLS handshake failed, the listener does not seem to use TLS: %w", err)
+	case errors.As(err, &hostnameErr):
+		return fmt.Errorf("TLS certificate does not match the host, set a server name override: %w", err)
+	case errors.As(err, &authorityErr), errors.As(err, &verifyErr):
+		return fmt.Errorf("TLS certificate verification failed, check the CA bundle: %w", err)
+	case errors.Is(err, kerr.SaslAuthenticationFailed):
+		return fmt.Errorf("SASL authentication failed, check the credentials and mechanism: %w", err)
+	case errors.Is(err, kerr.UnsupportedSaslMechanism), errors.Is(err, kerr.IllegalSaslState):
+		return fmt.Errorf("SASL mechanism rejected by the broker: %w", err)
+	case errors.Is(err, kerr.ClusterAuthorizationFailed):
+		return fmt.Errorf("connected, but not authorized to describe the cluster: %w", err)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("broker closed the connection, the listener may require TLS or SASL: %w", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out, check the address, firewall and whether the listener requires TLS: %w", err)
+	}
+	return err
+}
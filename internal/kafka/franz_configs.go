@@ -0,0 +1,112 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func (c *franzClient) GetTopicConfigs(ctx context.Context, topic string) ([]models.ConfigEntry, error) {
+	resourceConfigs, err := c.admin.DescribeTopicConfigs(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := resourceConfigs.On(topic, nil)
+	if err != nil {
+		return nil, fmt.Errorf("describe configs of topic %q: %w", topic, err)
+	}
+	if rc.Err != nil {
+		return nil, rc.Err
+	}
+	return toConfigEntries(rc.Configs), nil
+}
+
+func toConfigEntries(configs []kadm.Config) []models.ConfigEntry {
+	entries := make([]models.ConfigEntry, 0, len(configs))
+	for _, cfg := range configs {
+		entries = append(entries, models.ConfigEntry{
+			Name:      cfg.Key,
+			Value:     cfg.MaybeValue(),
+			Source:    toConfigSource(cfg.Source),
+			Sensitive: cfg.Sensitive,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+func toConfigSource(source kmsg.ConfigSource) models.ConfigSource {
+	switch source {
+	case kmsg.ConfigSourceDefaultConfig:
+		return models.ConfigSourceDefault
+	case kmsg.ConfigSourceStaticBrokerConfig:
+		return models.ConfigSourceStaticBroker
+	case kmsg.ConfigSourceDynamicBrokerConfig:
+		return models.ConfigSourceDynamicBroker
+	case kmsg.ConfigSourceDynamicDefaultBrokerConfig:
+		return models.ConfigSourceDynamicDefaultBroker
+	case kmsg.ConfigSourceDynamicTopicConfig:
+		return models.ConfigSourceDynamicTopic
+	default:
+		return models.ConfigSourceUnknown
+	}
+}
+
+func (c *franzClient) AlterTopicConfigs(ctx context.Context, topic string, alterations []models.ConfigAlteration) error {
+	resp, err := c.admin.AlterTopicConfigs(ctx, toAlterConfigs(alterations), topic)
+	if err != nil {
+		return err
+	}
+	return alterConfigsErr(resp, topic)
+}
+
+// ValidateTopicConfigs asks the broker to check the alterations without
+// applying them
+func (c *franzClient) ValidateTopicConfigs(ctx context.Context, topic string, alterations []models.ConfigAlteration) error {
+	resp, err := c.admin.ValidateAlterTopicConfigs(ctx, toAlterConfigs(alterations), topic)
+	if err != nil {
+		return err
+	}
+	return alterConfigsErr(resp, topic)
+}
+
+func toAlterConfigs(alterations []models.ConfigAlteration) []kadm.AlterConfig {
+	configs := make([]kadm.AlterConfig, len(alterations))
+	for i, alt := range alterations {
+		configs[i] = kadm.AlterConfig{Name: alt.Name}
+		switch alt.Op {
+		case models.ConfigDelete:
+			configs[i].Op = kadm.DeleteConfig
+		case models.ConfigAppend:
+			configs[i].Op = kadm.AppendConfig
+		case models.ConfigSubtract:
+			configs[i].Op = kadm.SubtractConfig
+		default:
+			configs[i].Op = kadm.SetConfig
+		}
+		if alt.Op != models.ConfigDelete {
+			configs[i].Value = strPtr(alt.Value)
+		}
+	}
+	return configs
+}
+
+func alterConfigsErr(resp kadm.AlterConfigsResponses, topic string) error {
+	for _, r := range resp {
+		if r.Err != nil {
+			if r.ErrMessage != "" {
+				return fmt.Errorf("alter configs of topic %q: %w: %s", topic, r.Err, r.ErrMessage)
+			}
+			return fmt.Errorf("alter configs of topic %q: %w", topic, r.Err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+var ErrGroupNotEmpty = errors.New("consumer group must be Empty")
+
+func (c *franzClient) ListConsumerGroups(ctx context.Context) ([]models.ConsumerGroup, error) {
+	listed, err := c.admin.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(listed) == 0 {
+		return []models.ConsumerGroup{}, nil
+	}
+
+	described, err := c.admin.DescribeGroups(ctx, listed.Groups()...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ConsumerGroup, 0, len(listed))
+	for _, lg := range listed.Sorted() {
+		cg := models.ConsumerGroup{
+			Name:         lg.Group,
+			State:        lg.State,
+			ProtocolType: lg.ProtocolType,
+			Coordinator:  int(lg.Coordinator),
+		}
+
+		if dg, ok := described[lg.Group]; ok && dg.Err == nil {
+			cg.State = dg.State
+			cg.Protocol = dg.Protocol
+			cg.Coordinator = int(dg.Coordinator.NodeID)
+			cg.Members = len(dg.Members)
+		}
+
+		result = append(result, cg)
+	}
+
+	return result, nil
+}
+
+func (c *franzClient) GetConsumerGroupOffsets(ctx context.Context, group string) ([]models.ConsumerGroupOffset, error) {
+	committed, err := c.admin.FetchOffsets(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	if err := committed.Error(); err != nil {
+		return nil, err
+	}
+
+	topics := committed.Partitions().Topics()
+	if len(topics) == 0 {
+		return []models.ConsumerGroupOffset{}, nil
+	}
+
+	endOffsets, err := c.admin.ListEndOffsets(ctx, topics...)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := committed.Sorted()
+	result := make([]models.ConsumerGroupOffset, 0, len(sorted))
+	for _, o := range sorted {
+		endOffset := int64(0)
+		if eo, ok := endOffsets.Lookup(o.Topic, o.Partition); ok && eo.Err == nil {
+			endOffset = eo.Offset
+		}
+
+		lag := endOffset - o.At
+		if o.At < 0 {
+			lag = endOffset
+		}
+		if lag < 0 {
+			lag = 0
+		}
+
+		result = append(result, models.ConsumerGroupOffset{
+			Topic:     o.Topic,
+			Partition: int(o.Partition),
+			Lag:       lag,
+			Offset:    o.At,
+			EndOffset: endOffset,
+		})
+	}
+
+	return result, nil
+}
+
+func (c *franzClient) GetConsumerGroupMembers(ctx context.Context, group string) ([]models.ConsumerGroupMember, error) {
+	described, err := c.admin.DescribeGroups(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	dg, ok := described[group]
+	if !ok {
+		return []models.ConsumerGroupMember{}, nil
+	}
+	if dg.Err != nil {
+		return nil, dg.Err
+	}
+
+	members := make([]models.ConsumerGroupMember, 0, len(dg.Members))
+	for _, m := range dg.Members {
+		member := models.ConsumerGroupMember{
+			MemberID: m.MemberID,
+			ClientID: m.ClientID,
+			Host:     m.ClientHost,
+		}
+		if m.InstanceID != nil {
+			member.InstanceID = *m.InstanceID
+		}
+
+		if assigned, ok := m.Assigned.AsConsumer(); ok {
+			for _, t := range assigned.Topics {
+				partitions := int32SliceToIntSlice(t.Partitions)
+				sort.Ints(partitions)
+				member.Assignments = append(member.Assignments, models.TopicPartitions{
+					Topic:      t.Topic,
+					Partitions: partitions,
+				})
+			}
+			sort.Slice(member.Assignments, func(i, j int) bool {
+				return member.Assignments[i].Topic < member.Assignments[j].Topic
+			})
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+func (c *franzClient) ensureGroupEmpty(ctx context.Context, group string) error {
+	described, err := c.admin.DescribeGroups(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	dg, ok := described[group]
+	if !ok {
+		return fmt.Errorf("consumer group %q not found", group)
+	}
+	if dg.Err != nil {
+		return dg.Err
+	}
+	if dg.State != models.GroupStateEmpty && dg.State != "Dead" {
+		return fmt.Errorf("%w, %q is %s", ErrGroupNotEmpty, group, dg.State)
+	}
+	return nil
+}
+
+func (c *franzClient) resetTargets(ctx context.Context, spec models.OffsetResetSpec, committed kadm.OffsetResponses) (kadm.TopicsList, error) {
+	if spec.Scope == models.ResetScopeGroup {
+		targets := committed.Partitions().Sorted()
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("consumer group %q has no committed offsets", spec.Group)
+		}
+		return targets, nil
+	}
+
+	topics, err := c.admin.ListTopics(ctx, spec.Topic)
+	if err != nil {
+		return nil, err
+	}
+	if !topics.Has(spec.Topic) {
+		return nil, fmt.Errorf("topic %q not found", spec.Topic)
+	}
+
+	targets := topics.TopicsList()
+	if spec.Scope == models.ResetScopePartition {
+		partition := int32(spec.Partition)
+		if _, ok := topics[spec.Topic].Partitions[partition]; !ok {
+			return nil, fmt.Errorf("partition %d not found in topic %q", spec.Partition, spec.Topic)
+		}
+		targets = kadm.TopicsList{{Topic: spec.Topic, Partitions: []int32{partition}}}
+	}
+	return targets, nil
+}
+
+func (c *franzClient) PreviewOffsetReset(ctx context.Context, spec models.OffsetResetSpec) ([]models.OffsetResetPreview, error) {
+	if err := c.ensureGroupEmpty(ctx, spec.Group); err != nil {
+		return nil, err
+	}
+
+	committed, err := c.admin.FetchOffsets(ctx, spec.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := c.resetTargets(ctx, spec, committed)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := targets.Topics()
+	startOffsets, err := c.admin.ListStartOffsets(ctx, topics...)
+	if err != nil {
+		return nil, err
+	}
+	endOffsets, err := c.admin.ListEndOffsets(ctx, topics...)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeOffsets kadm.ListedOffsets
+	if spec.Strategy == models.ResetToTimestamp {
+		timeOffsets, err = c.admin.ListOffsetsAfterMilli(ctx, spec.Timestamp, topics...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var previews []models.OffsetResetPreview
+	var lookupErr error
+	targets.Each(func(t string, p int32) {
+		start, ok := startOffsets.Lookup(t, p)
+		if !ok || start.Err != nil {
+			lookupErr = fmt.Errorf("listing start offset for %s/%d failed", t, p)
+			return
+		}
+		end, ok := endOffsets.Lookup(t, p)
+		if !ok || end.Err != nil {
+			lookupErr = fmt.Errorf("listing end offset for %s/%d failed", t, p)
+			return
+		}
+
+		oldOffset := int64(-1)
+		if o, ok := committed.Lookup(t, p); ok && o.Err == nil {
+			oldOffset = o.At
+		}
+
+		var newOffset int64
+		switch spec.Strategy {
+		case models.ResetToEarliest:
+			newOffset = start.Offset
+		case models.ResetToLatest:
+			newOffset = end.Offset
+		case models.ResetToOffset:
+			newOffset = spec.Offset
+		case models.ResetToTimestamp:
+			newOffset = end.Offset
+			if to, ok := timeOffsets.Lookup(t, p); ok && to.Err == nil && to.Offset >= 0 {
+				newOffset = to.Offset
+			}
+		case models.ResetShiftBy:
+			base := oldOffset
+			if base < 0 {
+				base = start.Offset
+			}
+			newOffset = base + spec.Shift
+		}
+
+		newOffset = max(newOffset, start.Offset)
+		newOffset = min(newOffset, end.Offset)
+
+		previews = append(previews, models.OffsetResetPreview{
+			Topic:     t,
+			Partition: int(p),
+			OldOffset: oldOffset,
+			NewOffset: newOffset,
+		})
+	})
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	return previews, nil
+}
+
+func (c *franzClient) ResetConsumerGroupOffsets(ctx context.Context, group string, previews []models.OffsetResetPreview) error {
+	if err := c.ensureGroupEmpty(ctx, group); err != nil {
+		return err
+	}
+
+	offsets := make(kadm.Offsets)
+	for _, p := range previews {
+		offsets.Add(kadm.Offset{
+			Topic:       p.Topic,
+			Partition:   int32(p.Partition),
+			At:          p.NewOffset,
+			LeaderEpoch: -1,
+		})
+	}
+
+	slog.Info("resetting consumer group offsets", slog.String("group", group), slog.Int("partitions", len(previews)))
+	return c.admin.CommitAllOffsets(ctx, group, offsets)
+}
+
+func (c *franzClient) DeleteConsumerGroup(ctx context.Context, group string) error {
+	slog.Info("deleting consumer group", slog.String("group", group))
+	resp, err := c.admin.DeleteGroup(ctx, group)
+	if err != nil {
+		return err
+	}
+	return resp.Err
+}
+
+func (c *franzClient) DeleteConsumerGroupOffsets(ctx context.Context, group, topic string) error {
+	committed, err := c.admin.FetchOffsetsForTopics(ctx, group, topic)
+	if err != nil {
+		return err
+	}
+
+	partitions := make(kadm.TopicsSet)
+	committed.Each(func(o kadm.OffsetResponse) {
+		if o.Topic == topic && o.Err == nil && o.At >= 0 {
+			partitions.Add(o.Topic, o.Partition)
+		}
+	})
+	if len(partitions) == 0 {
+		return fmt.Errorf("consumer group %q has no committed offsets for topic %q", group, topic)
+	}
+
+	slog.Info("deleting consumer group offsets", slog.String("group", group), slog.String("topic", topic))
+	resp, err := c.admin.DeleteOffsets(ctx, group, partitions)
+	if err != nil {
+		return err
+	}
+	return resp.Error()
+}
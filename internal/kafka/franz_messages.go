@@ -0,0 +1,262 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	fetchMaxWait = 500 * time.Millisecond
+	// pollIdleTimeout ends a read once no records arrive, e.g. when the
+	// remaining offsets of a partition are compacted or transaction markers
+	pollIdleTimeout = 2 * time.Second
+)
+
+// newConsumer builds a standalone consumer for the given partitions. It never
+// joins a group, so it can never commit offsets.
+func (c *franzClient) newConsumer(topic string, offsets map[int32]int64) (*kgo.Client, error) {
+	partitions := make(map[int32]kgo.Offset, len(offsets))
+	for p, o := range offsets {
+		partitions[p] = kgo.NewOffset().At(o)
+	}
+
+	opts := append([]kgo.Opt{}, c.opts...)
+	opts = append(opts,
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topic: partitions}),
+		kgo.FetchMaxWait(fetchMaxWait),
+	)
+	return kgo.NewClient(opts...)
+}
+
+// partitionRange is the half-open offset range [start, end) to read from a
+// partition
+type partitionRange struct {
+	start int64
+	end   int64
+}
+
+func (c *franzClient) messageRanges(ctx context.Context, query models.MessageQuery) (map[int32]partitionRange, error) {
+	startOffsets, err := c.admin.ListStartOffsets(ctx, query.Topic)
+	if err != nil {
+		return nil, err
+	}
+	endOffsets, err := c.admin.ListEndOffsets(ctx, query.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeOffsets map[int32]int64
+	if query.Start == models.StartTimestamp {
+		listed, err := c.admin.ListOffsetsAfterMilli(ctx, query.Timestamp, query.Topic)
+		if err != nil {
+			return nil, err
+		}
+		timeOffsets = make(map[int32]int64)
+		for p, lo := range listed[query.Topic] {
+			if lo.Err == nil && lo.Offset >= 0 {
+				timeOffsets[p] = lo.Offset
+			}
+		}
+	}
+
+	ranges := make(map[int32]partitionRange)
+	for p, so := range startOffsets[query.Topic] {
+		if query.Partition != models.AllPartitions && int32(query.Partition) != p {
+			continue
+		}
+		eo, ok := endOffsets.Lookup(query.Topic, p)
+		if so.Err != nil || !ok || eo.Err != nil {
+			continue
+		}
+
+		r := partitionRange{start: so.Offset, end: eo.Offset}
+		switch query.Start {
+		case models.StartLatest:
+			r.start = r.end - int64(query.Limit)
+		case models.StartOffset:
+			r.start = query.Offset
+		case models.StartTimestamp:
+			r.start = r.end
+			if o, ok := timeOffsets[p]; ok {
+				r.start = o
+			}
+		case models.StartFromOffsets:
+			o, ok := query.Offsets[int(p)]
+			if !ok {
+				continue
+			}
+			r.start = o
+		}
+		r.start = max(r.start, so.Offset)
+		r.start = min(r.start, r.end)
+
+		ranges[p] = r
+	}
+
+	if len(ranges) == 0 && query.Partition != models.AllPartitions {
+		return nil, fmt.Errorf("partition %d not found in topic %q", query.Partition, query.Topic)
+	}
+	return ranges, nil
+}
+
+// FetchMessages reads up to query.Limit records per partition and returns one
+// page ordered by timestamp. For StartLatest the newest records are kept,
+// otherwise the oldest.
+func (c *franzClient) FetchMessages(ctx context.Context, query models.MessageQuery) (models.MessagePage, error) {
+	if query.Limit <= 0 {
+		query.Limit = 1
+	}
+
+	ranges, err := c.messageRanges(ctx, query)
+	if err != nil {
+		return models.MessagePage{}, err
+	}
+
+	pending := make(map[int32]int64)
+	for p, r := range ranges {
+		if r.start < r.end {
+			pending[p] = r.start
+		}
+	}
+
+	var messages []models.Message
+	if len(pending) > 0 {
+		messages, err = c.consume(ctx, query, ranges, pending)
+		if err != nil {
+			return models.MessagePage{}, err
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		if !messages[i].Timestamp.Equal(messages[j].Timestamp) {
+			return messages[i].Timestamp.Before(messages[j].Timestamp)
+		}
+		if messages[i].Partition != messages[j].Partition {
+			return messages[i].Partition < messages[j].Partition
+		}
+		return messages[i].Offset < messages[j].Offset
+	})
+
+	if len(messages) > query.Limit {
+		if query.Start == models.StartLatest {
+			messages = messages[len(messages)-query.Limit:]
+		} else {
+			messages = messages[:query.Limit]
+		}
+	}
+
+	next := make(map[int]int64, len(ranges))
+	for p, r := range ranges {
+		if query.Start == models.StartLatest {
+			next[int(p)] = r.end
+		} else {
+			next[int(p)] = r.start
+		}
+	}
+	for _, m := range messages {
+		if m.Offset+1 > next[m.Partition] {
+			next[m.Partition] = m.Offset + 1
+		}
+	}
+
+	return models.MessagePage{Messages: messages, NextOffsets: next}, nil
+}
+
+func (c *franzClient) consume(ctx context.Context, query models.MessageQuery, ranges map[int32]partitionRange, pending map[int32]int64) ([]models.Message, error) {
+	consumer, err := c.newConsumer(query.Topic, pending)
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+
+	counts := make(map[int32]int)
+	var messages []models.Message
+
+	for len(pending) > 0 {
+		pollCtx, cancel := context.WithTimeout(ctx, pollIdleTimeout)
+		fetches := consumer.PollFetches(pollCtx)
+		cancel()
+		if pollCtx.Err() != nil && fetches.NumRecords() == 0 {
+			break
+		}
+		for _, fe := range fetches.Errors() {
+			if !errors.Is(fe.Err, context.DeadlineExceeded) && !errors.Is(fe.Err, context.Canceled) {
+				return nil, fe.Err
+			}
+		}
+
+		fetches.EachRecord(func(r *kgo.Record) {
+			if _, ok := pending[r.Partition]; !ok {
+				return
+			}
+
+			messages = append(messages, recordToMessage(r))
+			counts[r.Partition]++
+
+			if counts[r.Partition] >= query.Limit || r.Offset+1 >= ranges[r.Partition].end {
+				delete(pending, r.Partition)
+			}
+		})
+	}
+
+	return messages, nil
+}
+
+// TailMessages streams records produced to topic after the call, across all
+// partitions, until ctx is cancelled. onMessages runs on the polling
+// goroutine, so blocking in it pauses consumption.
+func (c *franzClient) TailMessages(ctx context.Context, topic string, onMessages func([]models.Message)) error {
+	opts := append([]kgo.Opt{}, c.opts...)
+	opts = append(opts,
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtEnd()),
+		kgo.FetchMaxWait(fetchMaxWait),
+	)
+	consumer, err := kgo.NewClient(opts...)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	for {
+		fetches := consumer.PollFetches(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		for _, fe := range fetches.Errors() {
+			if !errors.Is(fe.Err, context.Canceled) {
+				return fe.Err
+			}
+		}
+
+		messages := make([]models.Message, 0, fetches.NumRecords())
+		fetches.EachRecord(func(r *kgo.Record) {
+			messages = append(messages, recordToMessage(r))
+		})
+		if len(messages) > 0 {
+			onMessages(messages)
+		}
+	}
+}
+
+func recordToMessage(r *kgo.Record) models.Message {
+	headers := make([]models.MessageHeader, len(r.Headers))
+	for i, h := range r.Headers {
+		headers[i] = models.MessageHeader{Key: h.Key, Value: h.Value}
+	}
+
+	return models.Message{
+		Partition: int(r.Partition),
+		Offset:    r.Offset,
+		Timestamp: r.Timestamp,
+		Key:       r.Key,
+		Value:     r.Value,
+		Headers:   headers,
+	}
+}
@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// ProduceMessages sends records and waits for them to be acknowledged.
+// Records with an explicit partition go through a short-lived producer using
+// manual partitioning, the rest through the default partitioner.
+func (c *franzClient) ProduceMessages(ctx context.Context, records []models.ProduceRecord) ([]models.ProduceResult, error) {
+	var partitioned, unpartitioned []*kgo.Record
+	for _, r := range records {
+		record := toKgoRecord(r)
+		if r.Partition == models.DefaultPartitioner {
+			unpartitioned = append(unpartitioned, record)
+		} else {
+			partitioned = append(partitioned, record)
+		}
+	}
+
+	var results []models.ProduceResult
+	if len(unpartitioned) > 0 {
+		produced, err := produceSync(ctx, c.client, unpartitioned)
+		results = append(results, produced...)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	if len(partitioned) > 0 {
+		opts := append([]kgo.Opt{}, c.opts...)
+		opts = append(opts, kgo.RecordPartitioner(kgo.ManualPartitioner()))
+		producer, err := kgo.NewClient(opts...)
+		if err != nil {
+			return results, err
+		}
+		defer producer.Close()
+
+		produced, err := produceSync(ctx, producer, partitioned)
+		results = append(results, produced...)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+func produceSync(ctx context.Context, client *kgo.Client, records []*kgo.Record) ([]models.ProduceResult, error) {
+	produced := client.ProduceSync(ctx, records...)
+
+	results := make([]models.ProduceResult, 0, len(produced))
+	for _, pr := range produced {
+		if pr.Err == nil {
+			results = append(results, models.ProduceResult{
+				Partition: int(pr.Record.Partition),
+				Offset:    pr.Record.Offset,
+			})
+		}
+	}
+	return results, produced.FirstErr()
+}
+
+func toKgoRecord(r models.ProduceRecord) *kgo.Record {
+	headers := make([]kgo.RecordHeader, len(r.Headers))
+	for i, h := range r.Headers {
+		headers[i] = kgo.RecordHeader{Key: h.Key, Value: h.Value}
+	}
+
+	record := &kgo.Record{
+		Topic:   r.Topic,
+		Key:     r.Key,
+		Value:   r.Value,
+		Headers: headers,
+	}
+	if r.Partition != models.DefaultPartitioner {
+		record.Partition = int32(r.Partition)
+	}
+	return record
+}
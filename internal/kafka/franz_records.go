@@ -0,0 +1,112 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+// PreviewTruncate computes the new log start offset of every partition the
+// spec covers, clamped to the current [start, end] range
+func (c *franzClient) PreviewTruncate(ctx context.Context, spec models.TruncateSpec) ([]models.TruncatePreview, error) {
+	startOffsets, err := c.admin.ListStartOffsets(ctx, spec.Topic)
+	if err != nil {
+		return nil, err
+	}
+	endOffsets, err := c.admin.ListEndOffsets(ctx, spec.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeOffsets kadm.ListedOffsets
+	if spec.Target == models.TruncateToTimestamp {
+		timeOffsets, err = c.admin.ListOffsetsAfterMilli(ctx, spec.Timestamp, spec.Topic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var previews []models.TruncatePreview
+	for p, start := range startOffsets[spec.Topic] {
+		if spec.Partition != models.AllPartitions && int32(spec.Partition) != p {
+			continue
+		}
+		if start.Err != nil {
+			return nil, fmt.Errorf("listing start offset for %s/%d failed: %w", spec.Topic, p, start.Err)
+		}
+		end, ok := endOffsets.Lookup(spec.Topic, p)
+		if !ok || end.Err != nil {
+			return nil, fmt.Errorf("listing end offset for %s/%d failed", spec.Topic, p)
+		}
+
+		newStart := end.Offset
+		switch spec.Target {
+		case models.TruncateToOffset:
+			newStart = spec.Offset
+		case models.TruncateToTimestamp:
+			if to, ok := timeOffsets.Lookup(spec.Topic, p); ok && to.Err == nil && to.Offset >= 0 {
+				newStart = to.Offset
+			}
+		}
+		newStart = max(newStart, start.Offset)
+		newStart = min(newStart, end.Offset)
+
+		previews = append(previews, models.TruncatePreview{
+			Topic:          spec.Topic,
+			Partition:      int(p),
+			StartOffset:    start.Offset,
+			NewStartOffset: newStart,
+			EndOffset:      end.Offset,
+		})
+	}
+
+	if len(previews) == 0 && spec.Partition != models.AllPartitions {
+		return nil, fmt.Errorf("partition %d not found in topic %q", spec.Partition, spec.Topic)
+	}
+
+	sort.Slice(previews, func(i, j int) bool {
+		return previews[i].Partition < previews[j].Partition
+	})
+	return previews, nil
+}
+
+// DeleteRecords advances the log start offset of each previewed partition,
+// making every record before it unreadable
+func (c *franzClient) DeleteRecords(ctx context.Context, previews []models.TruncatePreview) error {
+	offsets := make(kadm.Offsets)
+	for _, p := range previews {
+		if p.DeletedRecords() <= 0 {
+			continue
+		}
+		offsets.Add(kadm.Offset{
+			Topic:     p.Topic,
+			Partition: int32(p.Partition),
+			At:        p.NewStartOffset,
+		})
+	}
+
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	slog.Info("deleting records", slog.Int("partitions", len(offsets)))
+	resp, err := c.admin.DeleteRecords(ctx, offsets)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, partitions := range resp {
+		for _, r := range partitions {
+			if r.Err != nil {
+				errs = append(errs, fmt.Errorf("delete records of %s/%d: %w", r.Topic, r.Partition, r.Err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
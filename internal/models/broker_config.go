@@ -1,5 +1,11 @@
 package models
 
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 type AuthType int
 
 const (
@@ -36,11 +42,87 @@ func (s SASLMechanism) String() string {
 	}
 }
 
+// SecretBackend selects where the secret of a broker is stored when its
+// password is neither inline nor an env:/cmd: reference
+type SecretBackend int
+
+const (
+	SecretKeyring SecretBackend = iota
+	SecretEncryptedFile
+)
+
+func (b SecretBackend) String() string {
+	if b == SecretEncryptedFile {
+		return "Encrypted file"
+	}
+	return "OS keyring"
+}
+
+// BrokerConfig describes how to reach a cluster. For OAUTHBEARER, Password
+// holds a static token unless OAuthTokenURL is set, in which case Username and
+// Password are the client ID and secret of a client credentials grant.
 type BrokerConfig struct {
-	Name             string        `json:"name"`
-	BootstrapServers string        `json:"bootstrap_servers"`
-	AuthType         AuthType      `json:"auth_type"`
-	SASLMechanism    SASLMechanism `json:"sasl_mechanism,omitempty"`
-	Username         string        `json:"username,omitempty"`
-	Password         string        `json:"password,omitempty"`
+	Name             string                `json:"name"`
+	BootstrapServers string                `json:"bootstrap_servers"`
+	AuthType         AuthType              `json:"auth_type"`
+	SASLMechanism    SASLMechanism         `json:"sasl_mechanism,omitempty"`
+	Username         string                `json:"username,omitempty"`
+	Password         string                `json:"password,omitempty"`
+	OAuthTokenURL    string                `json:"oauth_token_url,omitempty"`
+	OAuthScope       string                `json:"oauth_scope,omitempty"`
+	TLS              *TLSConfig            `json:"tls,omitempty"`
+	SecretBackend    SecretBackend         `json:"secret_backend,omitempty"`
+	SchemaRegistry   *SchemaRegistryConfig `json:"schema_registry,omitempty"`
+}
+
+// SchemaRegistryConfig points a broker at the Schema Registry holding the
+// schemas of its topics. Username and Password enable basic auth, BearerToken
+// sends an Authorization: Bearer header instead. Both secrets may be env: or
+// cmd: references.
+type SchemaRegistryConfig struct {
+	URL         string     `json:"url"`
+	Username    string     `json:"username,omitempty"`
+	Password    string     `json:"password,omitempty"`
+	BearerToken string     `json:"bearer_token,omitempty"`
+	TLS         *TLSConfig `json:"tls,omitempty"`
+}
+
+// TLSConfig enables TLS on broker connections. CertFile and KeyFile together
+// enable mutual TLS. Paths may start with ~/ to refer to the home directory.
+type TLSConfig struct {
+	Enabled            bool   `json:"enabled"`
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// TLSEnabled reports whether connections to the cluster use TLS
+func (c BrokerConfig) TLSEnabled() bool {
+	return c.TLS != nil && c.TLS.Enabled
+}
+
+// HasSchemaRegistry reports whether a Schema Registry is configured for the
+// cluster
+func (c BrokerConfig) HasSchemaRegistry() bool {
+	return c.SchemaRegistry != nil && c.SchemaRegistry.URL != ""
+}
+
+// ExpandHome replaces a leading ~/ in path with the user's home directory
+func ExpandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// UsesOAuthClientCredentials reports whether the OAUTHBEARER token is fetched
+// from a token endpoint instead of being configured statically
+func (c BrokerConfig) UsesOAuthClientCredentials() bool {
+	return c.AuthType == AuthSASL && c.SASLMechanism == SASLOAuthBearer && c.OAuthTokenURL != ""
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"net"
+	"strconv"
+)
+
+type BrokerNode struct {
+	ID   int32
+	Host string
+	Port int32
+	Rack string
+	// Version is the Kafka version guessed from the broker's API versions
+	Version string
+	// Leaders and Replicas count the partitions the broker leads and hosts
+	Leaders  int
+	Replicas int
+}
+
+func (n BrokerNode) Address() string {
+	return net.JoinHostPort(n.Host, strconv.Itoa(int(n.Port)))
+}
+
+// APIVersion is the range of versions a broker supports for one request
+type APIVersion struct {
+	Key        int16
+	Name       string
+	MinVersion int16
+	MaxVersion int16
+}
+
+// ClusterInfo summarizes a cluster from its metadata and ApiVersions
+type ClusterInfo struct {
+	ClusterID    string
+	ControllerID int32
+	Brokers      []BrokerNode
+	// Version is the Kafka version guessed from the API versions the
+	// controller supports
+	Version string
+	// APIVersions lists the requests the controller supports
+	APIVersions []APIVersion
+}
+
+// Controller returns the controller broker, if the cluster reported one
+func (c ClusterInfo) Controller() (BrokerNode, bool) {
+	for _, b := range c.Brokers {
+		if b.ID == c.ControllerID {
+			return b, true
+		}
+	}
+	return BrokerNode{}, false
+}
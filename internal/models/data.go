@@ -17,6 +17,7 @@ type Topic struct {
 	CleanUpPolicy  string
 	MessageCount   int64
 	IsInternal     bool
+	Configs        []ConfigEntry
 }
 
 type Partition struct {
@@ -30,9 +31,25 @@ type Partition struct {
 }
 
 type ConsumerGroup struct {
-	Name    string
-	State   string
-	Members int
+	Name         string
+	State        string
+	ProtocolType string
+	Protocol     string
+	Coordinator  int
+	Members      int
+}
+
+type ConsumerGroupMember struct {
+	MemberID    string
+	InstanceID  string
+	ClientID    string
+	Host        string
+	Assignments []TopicPartitions
+}
+
+type TopicPartitions struct {
+	Topic      string
+	Partitions []int
 }
 
 type ConsumerGroupOffset struct {
@@ -40,11 +57,13 @@ type ConsumerGroupOffset struct {
 	Partition int
 	Lag       int64
 	Offset    int64
+	EndOffset int64
 }
 
 type SchemaRegistry struct {
 	Subject string
 	Version int
+	ID      int
 	Type    string
 	Schema  string
 }
@@ -77,92 +96,3 @@ func MockPartitions(topicName string, count int) []Partition {
 	}
 	return partitions
 }
-
-func MockConsumerGroups() []ConsumerGroup {
-	return []ConsumerGroup{
-		{Name: "order-processor", State: "Stable", Members: 3},
-		{Name: "payment-handler", State: "Stable", Members: 2},
-		{Name: "notification-sender", State: "Rebalancing", Members: 4},
-		{Name: "analytics-consumer", State: "Stable", Members: 6},
-	}
-}
-
-func MockConsumerGroupOffsets(groupName string) []ConsumerGroupOffset {
-	return []ConsumerGroupOffset{
-		{Topic: "orders", Partition: 0, Lag: 0, Offset: 500},
-		{Topic: "orders", Partition: 1, Lag: 10, Offset: 490},
-		{Topic: "orders", Partition: 2, Lag: 5, Offset: 495},
-		{Topic: "payments", Partition: 0, Lag: 0, Offset: 1000},
-	}
-}
-
-func MockSchemaRegistries() []SchemaRegistry {
-	return []SchemaRegistry{
-		{
-			Subject: "orders-value",
-			Version: 3,
-			Type:    "AVRO",
-			Schema: `{
-  "type": "record",
-  "name": "Order",
-  "namespace": "com.example.orders",
-  "fields": [
-    {
-      "name": "id",
-      "type": "string"
-    },
-    {
-      "name": "amount",
-      "type": {
-        "type": "int",
-        "connect.default": 0
-      },
-      "default": 0
-    },
-    {
-      "name": "status",
-      "type": "string"
-    }
-  ]
-}`,
-		},
-		{
-			Subject: "payments-value",
-			Version: 2,
-			Type:    "AVRO",
-			Schema: `{
-  "type": "record",
-  "name": "Payment",
-  "namespace": "com.example.payments",
-  "fields": [
-    {
-      "name": "id",
-      "type": "string"
-    },
-    {
-      "name": "orderId",
-      "type": "string"
-    },
-    {
-      "name": "amount",
-      "type": "double"
-    }
-  ]
-}`,
-		},
-		{
-			Subject: "users-value",
-			Version: 5,
-			Type:    "JSON",
-			Schema: `{
-  "type": "object",
-  "properties": {
-    "id": {"type": "string"},
-    "name": {"type": "string"},
-    "email": {"type": "string"}
-  },
-  "required": ["id", "name"]
-}`,
-		},
-	}
-}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+type MessageHeader struct {
+	Key   string
+	Value []byte
+}
+
+type Message struct {
+	Partition int
+	Offset    int64
+	Timestamp time.Time
+	Key       []byte
+	Value     []byte
+	Headers   []MessageHeader
+	// DecodedKey and DecodedValue hold the key and value as JSON when they
+	// were decoded with a schema from the registry
+	DecodedKey   string
+	DecodedValue string
+}
+
+type MessageStartPosition int
+
+const (
+	StartEarliest MessageStartPosition = iota
+	StartLatest
+	StartOffset
+	StartTimestamp
+	StartFromOffsets
+)
+
+func (p MessageStartPosition) String() string {
+	switch p {
+	case StartLatest:
+		return "latest"
+	case StartOffset:
+		return "offset"
+	case StartTimestamp:
+		return "timestamp"
+	case StartFromOffsets:
+		return "continue"
+	default:
+		return "earliest"
+	}
+}
+
+// AllPartitions selects every partition of a topic in a MessageQuery
+const AllPartitions = -1
+
+type MessageQuery struct {
+	Topic     string
+	Partition int
+	Start     MessageStartPosition
+	Offset    int64
+	Timestamp int64
+	// Offsets holds the per-partition start offsets for StartFromOffsets
+	Offsets map[int]int64
+	Limit   int
+}
+
+type MessagePage struct {
+	Messages []Message
+	// NextOffsets holds the per-partition offsets the following page starts at
+	NextOffsets map[int]int64
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const GroupStateEmpty = "Empty"
+
+type OffsetResetScope int
+
+const (
+	ResetScopeGroup OffsetResetScope = iota
+	ResetScopeTopic
+	ResetScopePartition
+)
+
+func (s OffsetResetScope) String() string {
+	switch s {
+	case ResetScopeTopic:
+		return "topic"
+	case ResetScopePartition:
+		return "partition"
+	default:
+		return "group"
+	}
+}
+
+type OffsetResetStrategy int
+
+const (
+	ResetToEarliest OffsetResetStrategy = iota
+	ResetToLatest
+	ResetToOffset
+	ResetToTimestamp
+	ResetShiftBy
+)
+
+func (s OffsetResetStrategy) String() string {
+	switch s {
+	case ResetToLatest:
+		return "latest"
+	case ResetToOffset:
+		return "offset"
+	case ResetToTimestamp:
+		return "timestamp"
+	case ResetShiftBy:
+		return "shift-by"
+	default:
+		return "earliest"
+	}
+}
+
+type OffsetResetSpec struct {
+	Group     string
+	Scope     OffsetResetScope
+	Topic     string
+	Partition int
+	Strategy  OffsetResetStrategy
+	Offset    int64
+	Timestamp int64
+	Shift     int64
+}
+
+type OffsetResetPreview struct {
+	Topic     string
+	Partition int
+	OldOffset int64
+	NewOffset int64
+}
+
+// ParseTimestamp accepts RFC3339, "2006-01-02 15:04:05", "2006-01-02" or
+// unix milliseconds and returns unix milliseconds
+func ParseTimestamp(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("timestamp is required")
+	}
+
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ms, nil
+	}
+
+	layouts := []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid timestamp %q, use RFC3339, 2006-01-02 15:04:05 or unix millis", s)
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultPartitioner leaves the partition of a ProduceRecord to the producer,
+// which hashes the key or spreads keyless records
+const DefaultPartitioner = -1
+
+type ProduceRecord struct {
+	Topic     string
+	Partition int
+	Key       []byte
+	Value     []byte
+	Headers   []MessageHeader
+}
+
+type ProduceResult struct {
+	Partition int
+	Offset    int64
+}
+
+// ParseHeaders parses headers written as "key=value,key2=value2"
+func ParseHeaders(s string) ([]MessageHeader, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	var headers []MessageHeader
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header %q, expected key=value", strings.TrimSpace(pair))
+		}
+		headers = append(headers, MessageHeader{Key: key, Value: []byte(value)})
+	}
+	return headers, nil
+}
+
+// ParseRecordLines reads one record per non-empty line. When keySeparator is
+// set, the text before its first occurrence becomes the record key.
+func ParseRecordLines(r io.Reader, keySeparator string) ([]ProduceRecord, error) {
+	var records []ProduceRecord
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		record := ProduceRecord{Value: []byte(text)}
+		if keySeparator != "" {
+			key, value, ok := strings.Cut(text, keySeparator)
+			if !ok {
+				return nil, fmt.Errorf("line %d: key separator %q not found", line, keySeparator)
+			}
+			record.Key = []byte(key)
+			record.Value = []byte(value)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("no records found")
+	}
+	return records, nil
+}
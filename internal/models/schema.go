@@ -0,0 +1,43 @@
+package models
+
+type SchemaChangeKind int
+
+const (
+	SchemaFieldAdded SchemaChangeKind = iota
+	SchemaFieldRemoved
+	SchemaFieldChanged
+	SchemaFieldRenamed
+)
+
+func (k SchemaChangeKind) Symbol() string {
+	switch k {
+	case SchemaFieldAdded:
+		return "+"
+	case SchemaFieldRemoved:
+		return "-"
+	case SchemaFieldRenamed:
+		return ">"
+	default:
+		return "~"
+	}
+}
+
+// SchemaChange is one structural difference between two schema versions.
+// Path names a field as Record.field for Avro, a.b.c for JSON Schema and
+// Message.field for Protobuf. Old and New describe its type, they are empty
+// for added and removed fields respectively. A renamed field keeps its new
+// name in RenamedTo.
+type SchemaChange struct {
+	Kind      SchemaChangeKind
+	Path      string
+	Old       string
+	New       string
+	RenamedTo string
+}
+
+// CompatibilityResult is the outcome of checking a schema against the latest
+// version of its subject. Messages explain the violations, if any.
+type CompatibilityResult struct {
+	Compatible bool
+	Messages   []string
+}
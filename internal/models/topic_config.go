@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -37,6 +38,48 @@ type TopicConfig struct {
 	RetentionMs       int64
 }
 
+type ConfigSource int
+
+const (
+	ConfigSourceUnknown ConfigSource = iota
+	ConfigSourceDefault
+	ConfigSourceStaticBroker
+	ConfigSourceDynamicBroker
+	ConfigSourceDynamicDefaultBroker
+	ConfigSourceDynamicTopic
+)
+
+func (s ConfigSource) String() string {
+	switch s {
+	case ConfigSourceDefault:
+		return "default"
+	case ConfigSourceStaticBroker:
+		return "static broker"
+	case ConfigSourceDynamicBroker:
+		return "dynamic broker"
+	case ConfigSourceDynamicDefaultBroker:
+		return "dynamic default broker"
+	case ConfigSourceDynamicTopic:
+		return "dynamic topic"
+	default:
+		return "unknown"
+	}
+}
+
+// ConfigEntry is a single described config of a topic
+type ConfigEntry struct {
+	Name      string
+	Value     string
+	Source    ConfigSource
+	Sensitive bool
+}
+
+// IsOverride reports whether the value is set on the topic itself rather
+// than inherited from the broker or the built-in default
+func (e ConfigEntry) IsOverride() bool {
+	return e.Source == ConfigSourceDynamicTopic
+}
+
 func ParseRetention(s string) (int64, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -57,3 +100,115 @@ func ParseRetention(s string) (int64, error) {
 	}
 	return d.Milliseconds(), nil
 }
+
+type ConfigAlterOp int
+
+const (
+	ConfigSet ConfigAlterOp = iota
+	ConfigDelete
+	ConfigAppend
+	ConfigSubtract
+)
+
+func (o ConfigAlterOp) String() string {
+	switch o {
+	case ConfigDelete:
+		return "delete"
+	case ConfigAppend:
+		return "append"
+	case ConfigSubtract:
+		return "subtract"
+	default:
+		return "set"
+	}
+}
+
+// ConfigAlteration is a single incremental change to a topic config
+type ConfigAlteration struct {
+	Name  string
+	Op    ConfigAlterOp
+	Value string
+}
+
+// listConfigs are the topic configs holding comma separated lists, the only
+// ones append and subtract apply to
+var listConfigs = map[string]bool{
+	"cleanup.policy":                          true,
+	"leader.replication.throttled.replicas":   true,
+	"follower.replication.throttled.replicas": true,
+}
+
+func IsListConfig(name string) bool {
+	return listConfigs[name]
+}
+
+// NormalizeConfigValue validates the value of well-known configs and converts
+// it to the form the broker expects. Durations of *.ms configs may be given
+// as 7d or 168h, retention.ms also takes -1 for unlimited.
+func NormalizeConfigValue(name, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case name == "cleanup.policy":
+		for _, p := range strings.Split(value, ",") {
+			if p := strings.TrimSpace(p); p != "delete" && p != "compact" {
+				return "", fmt.Errorf("invalid cleanup policy %q, expected delete or compact", p)
+			}
+		}
+		return value, nil
+	case strings.HasSuffix(name, ".ms"):
+		ms, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			if ms, err = ParseRetention(value); err != nil {
+				return "", fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		// other *.ms configs allow zero or other negative values, the broker
+		// validates those
+		if name == "retention.ms" && ms <= 0 && ms != -1 {
+			return "", fmt.Errorf("%s must be positive or -1", name)
+		}
+		return strconv.FormatInt(ms, 10), nil
+	case strings.HasSuffix(name, ".bytes"), name == "min.insync.replicas":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "", fmt.Errorf("%s must be an integer", name)
+		}
+		return value, nil
+	}
+	return value, nil
+}
+
+// ApplyConfigAlteration returns the value a config ends up with after the
+// alteration, or ok=false when it reverts to its inherited default
+func ApplyConfigAlteration(current string, alt ConfigAlteration) (value string, ok bool) {
+	switch alt.Op {
+	case ConfigDelete:
+		return "", false
+	case ConfigAppend:
+		items := splitList(current)
+		for _, v := range splitList(alt.Value) {
+			if !slices.Contains(items, v) {
+				items = append(items, v)
+			}
+		}
+		return strings.Join(items, ","), true
+	case ConfigSubtract:
+		remove := splitList(alt.Value)
+		items := slices.DeleteFunc(splitList(current), func(v string) bool {
+			return slices.Contains(remove, v)
+		})
+		return strings.Join(items, ","), true
+	default:
+		return alt.Value, true
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+	return items
+}
@@ -0,0 +1,43 @@
+package models
+
+type TruncateTarget int
+
+const (
+	TruncateToEnd TruncateTarget = iota
+	TruncateToOffset
+	TruncateToTimestamp
+)
+
+func (t TruncateTarget) String() string {
+	switch t {
+	case TruncateToOffset:
+		return "offset"
+	case TruncateToTimestamp:
+		return "timestamp"
+	default:
+		return "end"
+	}
+}
+
+// TruncateSpec describes up to where records of a topic are deleted.
+// Partition is AllPartitions to truncate every partition.
+type TruncateSpec struct {
+	Topic     string
+	Partition int
+	Target    TruncateTarget
+	Offset    int64
+	Timestamp int64
+}
+
+// TruncatePreview is the log start offset a partition moves to
+type TruncatePreview struct {
+	Topic          string
+	Partition      int
+	StartOffset    int64
+	NewStartOffset int64
+	EndOffset      int64
+}
+
+func (p TruncatePreview) DeletedRecords() int64 {
+	return p.NewStartOffset - p.StartOffset
+}
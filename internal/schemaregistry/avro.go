@@ -0,0 +1,322 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maxAvroItems bounds the elements of an array or map, which is all that
+// stops a corrupt block count when the elements take no bytes, e.g. nulls
+const maxAvroItems = 1 << 20
+
+// avroDecoder reads Avro binary encoded data written with a schema. Unions
+// render as the value of the chosen branch and bytes as base64.
+type avroDecoder struct {
+	root  any
+	named map[string]avroNamedType
+}
+
+// avroNamedType is a record, enum or fixed along with the namespace it is
+// defined in, which relative names in its fields resolve against
+type avroNamedType struct {
+	def       map[string]any
+	namespace string
+}
+
+func newAvroDecoder(schema string) (*avroDecoder, error) {
+	var root any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	d := &avroDecoder{root: root, named: make(map[string]avroNamedType)}
+	d.register(root, "")
+	return d, nil
+}
+
+// register indexes the named types reachable from t by their full name
+func (d *avroDecoder) register(t any, namespace string) {
+	switch t := t.(type) {
+	case []any:
+		for _, branch := range t {
+			d.register(branch, namespace)
+		}
+	case map[string]any:
+		switch kind, _ := t["type"].(string); kind {
+		case "record", "error", "enum", "fixed":
+			name, ns := avroName(t, namespace)
+			if _, ok := d.named[name]; ok {
+				return
+			}
+			d.named[name] = avroNamedType{def: t, namespace: namespace}
+			fields, _ := t["fields"].([]any)
+			for _, raw := range fields {
+				if field, ok := raw.(map[string]any); ok {
+					d.register(field["type"], ns)
+				}
+			}
+		case "array":
+			d.register(t["items"], namespace)
+		case "map":
+			d.register(t["values"], namespace)
+		}
+	}
+}
+
+func (d *avroDecoder) decode(payload []byte) (any, error) {
+	r := &avroReader{buf: payload}
+	return d.read(r, d.root, "")
+}
+
+func (d *avroDecoder) read(r *avroReader, t any, namespace string) (any, error) {
+	switch t := t.(type) {
+	case string:
+		return d.readNamed(r, t, namespace)
+	case []any:
+		index, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 || index >= int64(len(t)) {
+			return nil, fmt.Errorf("union branch %d out of range", index)
+		}
+		return d.read(r, t[index], namespace)
+	case map[string]any:
+		kind, ok := t["type"].(string)
+		if !ok {
+			return d.read(r, t["type"], namespace)
+		}
+		switch kind {
+		case "record", "error":
+			return d.readRecord(r, t, namespace)
+		case "enum":
+			index, err := r.long()
+			if err != nil {
+				return nil, err
+			}
+			symbols, _ := t["symbols"].([]any)
+			if index < 0 || index >= int64(len(symbols)) {
+				return nil, fmt.Errorf("enum symbol %d out of range", index)
+			}
+			return symbols[index], nil
+		case "array":
+			var items []any
+			err := r.blocks(!d.zeroWidth(t["items"], namespace, 0), func() error {
+				item, err := d.read(r, t["items"], namespace)
+				items = append(items, item)
+				return err
+			})
+			if items == nil {
+				items = []any{}
+			}
+			return items, err
+		case "map":
+			entries := orderedObject{}
+			// every entry holds at least the length of its key
+			err := r.blocks(true, func() error {
+				key, err := r.string()
+				if err != nil {
+					return err
+				}
+				value, err := d.read(r, t["values"], namespace)
+				entries.set(key, value)
+				return err
+			})
+			return entries, err
+		case "fixed":
+			size, _ := t["size"].(float64)
+			return r.bytes(int(size))
+		default:
+			// a primitive annotated with a logical type
+			return d.readNamed(r, kind, namespace)
+		}
+	}
+	return nil, fmt.Errorf("invalid Avro type %v", t)
+}
+
+func (d *avroDecoder) readRecord(r *avroReader, t map[string]any, namespace string) (any, error) {
+	_, ns := avroName(t, namespace)
+	fields, _ := t["fields"].([]any)
+	record := make(orderedObject, 0, len(fields))
+	for _, raw := range fields {
+		field, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := field["name"].(string)
+		value, err := d.read(r, field["type"], ns)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		record = append(record, objectMember{name: name, value: value})
+	}
+	return record, nil
+}
+
+func (d *avroDecoder) readNamed(r *avroReader, name, namespace string) (any, error) {
+	switch name {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := r.bytes(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		return r.long()
+	case "float":
+		b, err := r.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return jsonFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))), nil
+	case "double":
+		b, err := r.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return jsonFloat(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+	case "bytes":
+		n, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		return r.bytes(int(n))
+	case "string":
+		return r.string()
+	}
+
+	named, ok := d.lookup(name, namespace)
+	if !ok {
+		return nil, fmt.Errorf("unknown Avro type %s", name)
+	}
+	return d.read(r, named.def, named.namespace)
+}
+
+func (d *avroDecoder) lookup(name, namespace string) (avroNamedType, bool) {
+	named, ok := d.named[name]
+	if !ok && !strings.Contains(name, ".") {
+		named, ok = d.named[qualify(namespace, name)]
+	}
+	return named, ok
+}
+
+// zeroWidth reports whether values of t may be encoded in no bytes at all:
+// null, empty fixed and records of nothing else. depth stops recursive
+// records, which always take bytes somewhere.
+func (d *avroDecoder) zeroWidth(t any, namespace string, depth int) bool {
+	if depth > 32 {
+		return false
+	}
+	switch t := t.(type) {
+	case string:
+		if t == "null" {
+			return true
+		}
+		named, ok := d.lookup(t, namespace)
+		return ok && d.zeroWidth(named.def, named.namespace, depth+1)
+	case map[string]any:
+		kind, ok := t["type"].(string)
+		if !ok {
+			return d.zeroWidth(t["type"], namespace, depth+1)
+		}
+		switch kind {
+		case "null":
+			return true
+		case "fixed":
+			size, _ := t["size"].(float64)
+			return size == 0
+		case "record", "error":
+			_, ns := avroName(t, namespace)
+			fields, _ := t["fields"].([]any)
+			for _, raw := range fields {
+				field, ok := raw.(map[string]any)
+				if ok && !d.zeroWidth(field["type"], ns, depth+1) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+type avroReader struct {
+	buf []byte
+}
+
+func (r *avroReader) long() (int64, error) {
+	v, n := binary.Varint(r.buf)
+	if n == 0 {
+		return 0, errTruncated
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	r.buf = r.buf[n:]
+	return v, nil
+}
+
+func (r *avroReader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.buf) {
+		return nil, errTruncated
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b, nil
+}
+
+func (r *avroReader) string() (string, error) {
+	n, err := r.long()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(n))
+	return string(b), err
+}
+
+// blocks reads the blocks arrays and maps are encoded in, calling item once
+// per element. A negative count is followed by the block size in bytes.
+// When every element consumes input the count cannot exceed the bytes left.
+func (r *avroReader) blocks(consumes bool, item func() error) error {
+	var total int64
+	for {
+		count, err := r.long()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			count = -count
+			if _, err := r.long(); err != nil {
+				return err
+			}
+		}
+		if count < 0 || consumes && count > int64(len(r.buf)) {
+			return fmt.Errorf("invalid block count %d", count)
+		}
+		total += count
+		if total > maxAvroItems {
+			return fmt.Errorf("more than %d items in an array or map", maxAvroItems)
+		}
+		for range count {
+			if err := item(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// jsonFloat keeps NaN and infinities, which JSON has no numbers for, as
+// strings
+func jsonFloat(f float64) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprint(f)
+	}
+	return f
+}
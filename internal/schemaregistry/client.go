@@ -0,0 +1,300 @@
+// Package schemaregistry talks to Schema Registries implementing the Confluent
+// REST API
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tlsconfig"
+)
+
+const requestTimeout = 15 * time.Second
+
+// SchemaTypeAvro is reported by the registry by omitting the schema type
+const SchemaTypeAvro = "AVRO"
+
+// CompatibilityLevels lists the levels a registry accepts
+var CompatibilityLevels = []string{
+	"BACKWARD",
+	"BACKWARD_TRANSITIVE",
+	"FORWARD",
+	"FORWARD_TRANSITIVE",
+	"FULL",
+	"FULL_TRANSITIVE",
+	"NONE",
+}
+
+type Client interface {
+	ListSubjects(ctx context.Context) ([]string, error)
+	ListVersions(ctx context.Context, subject string) ([]int, error)
+	GetSchema(ctx context.Context, subject string, version int) (models.SchemaRegistry, error)
+	GetLatestSchema(ctx context.Context, subject string) (models.SchemaRegistry, error)
+	GetSchemaByID(ctx context.Context, id int) (models.SchemaRegistry, error)
+	// GetCompatibility returns the compatibility level of subject, or the
+	// global level when subject is empty. It is empty for subjects using the
+	// global level.
+	GetCompatibility(ctx context.Context, subject string) (string, error)
+	// SetCompatibility sets the level of subject, or the global level when
+	// subject is empty
+	SetCompatibility(ctx context.Context, subject, level string) error
+	// ResetCompatibility makes subject use the global level again
+	ResetCompatibility(ctx context.Context, subject string) error
+	// DeleteSubject deletes every version of subject. Soft deleted versions
+	// can still be read by ID, permanent deletion removes them for good.
+	DeleteSubject(ctx context.Context, subject string, permanent bool) error
+	// DeleteVersion deletes one version of subject, see DeleteSubject
+	DeleteVersion(ctx context.Context, subject string, version int, permanent bool) error
+	// CheckCompatibility tests schema against the latest version of its
+	// subject. A subject without versions accepts any schema.
+	CheckCompatibility(ctx context.Context, schema models.SchemaRegistry) (models.CompatibilityResult, error)
+	// RegisterSchema registers schema under its subject and returns its ID
+	RegisterSchema(ctx context.Context, schema models.SchemaRegistry) (int, error)
+}
+
+type restClient struct {
+	baseURL     string
+	username    string
+	password    string
+	bearerToken string
+	httpClient  *http.Client
+}
+
+// NewClient creates a client for the registry described by config
+func NewClient(config models.SchemaRegistryConfig) (Client, error) {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	if config.TLS != nil && config.TLS.Enabled {
+		tlsConfig, err := tlsconfig.New(*config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		httpClient.Transport = transport
+	}
+	return NewClientWithHTTPClient(config, httpClient)
+}
+
+// NewClientWithHTTPClient creates a client that sends its requests through
+// httpClient, whose transport settings take precedence over config.TLS
+func NewClientWithHTTPClient(config models.SchemaRegistryConfig, httpClient *http.Client) (Client, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema registry URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid schema registry URL %q: scheme must be http or https", config.URL)
+	}
+
+	return &restClient{
+		baseURL:     strings.TrimRight(config.URL, "/"),
+		username:    config.Username,
+		password:    config.Password,
+		bearerToken: config.BearerToken,
+		httpClient:  httpClient,
+	}, nil
+}
+
+type schemaResponse struct {
+	Subject    string `json:"subject"`
+	Version    int    `json:"version"`
+	ID         int    `json:"id"`
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+func (r schemaResponse) toModel() models.SchemaRegistry {
+	schemaType := r.SchemaType
+	if schemaType == "" {
+		schemaType = SchemaTypeAvro
+	}
+	return models.SchemaRegistry{
+		Subject: r.Subject,
+		Version: r.Version,
+		ID:      r.ID,
+		Type:    schemaType,
+		Schema:  r.Schema,
+	}
+}
+
+type schemaRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+func newSchemaRequest(schema models.SchemaRegistry) schemaRequest {
+	req := schemaRequest{Schema: schema.Schema}
+	// registries predating schema types only accept Avro without one
+	if schema.Type != SchemaTypeAvro {
+		req.SchemaType = schema.Type
+	}
+	return req
+}
+
+type compatibilityResponse struct {
+	IsCompatible bool     `json:"is_compatible"`
+	Messages     []string `json:"messages"`
+}
+
+type registerResponse struct {
+	ID int `json:"id"`
+}
+
+type configResponse struct {
+	CompatibilityLevel string `json:"compatibilityLevel"`
+}
+
+type configRequest struct {
+	Compatibility string `json:"compatibility"`
+}
+
+func (c *restClient) ListSubjects(ctx context.Context) ([]string, error) {
+	var subjects []string
+	if err := c.do(ctx, http.MethodGet, "/subjects", nil, &subjects); err != nil {
+		return nil, fmt.Errorf("list subjects: %w", err)
+	}
+	return subjects, nil
+}
+
+func (c *restClient) ListVersions(ctx context.Context, subject string) ([]int, error) {
+	var versions []int
+	if err := c.do(ctx, http.MethodGet, subjectPath(subject, "versions"), nil, &versions); err != nil {
+		return nil, fmt.Errorf("list versions of %s: %w", subject, err)
+	}
+	return versions, nil
+}
+
+func (c *restClient) GetSchema(ctx context.Context, subject string, version int) (models.SchemaRegistry, error) {
+	return c.getSchema(ctx, subject, strconv.Itoa(version))
+}
+
+func (c *restClient) GetLatestSchema(ctx context.Context, subject string) (models.SchemaRegistry, error) {
+	return c.getSchema(ctx, subject, "latest")
+}
+
+func (c *restClient) getSchema(ctx context.Context, subject, version string) (models.SchemaRegistry, error) {
+	var resp schemaResponse
+	if err := c.do(ctx, http.MethodGet, subjectPath(subject, "versions", version), nil, &resp); err != nil {
+		return models.SchemaRegistry{}, fmt.Errorf("get %s version %s: %w", subject, version, err)
+	}
+	return resp.toModel(), nil
+}
+
+func (c *restClient) GetSchemaByID(ctx context.Context, id int) (models.SchemaRegistry, error) {
+	var resp schemaResponse
+	if err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &resp); err != nil {
+		return models.SchemaRegistry{}, fmt.Errorf("get schema %d: %w", id, err)
+	}
+	resp.ID = id
+	return resp.toModel(), nil
+}
+
+func (c *restClient) GetCompatibility(ctx context.Context, subject string) (string, error) {
+	var resp configResponse
+	err := c.do(ctx, http.MethodGet, configPath(subject), nil, &resp)
+	if subject != "" && IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get compatibility of %s: %w", describeSubject(subject), err)
+	}
+	return resp.CompatibilityLevel, nil
+}
+
+func (c *restClient) SetCompatibility(ctx context.Context, subject, level string) error {
+	if err := c.do(ctx, http.MethodPut, configPath(subject), configRequest{Compatibility: level}, nil); err != nil {
+		return fmt.Errorf("set compatibility of %s: %w", describeSubject(subject), err)
+	}
+	return nil
+}
+
+func (c *restClient) ResetCompatibility(ctx context.Context, subject string) error {
+	err := c.do(ctx, http.MethodDelete, configPath(subject), nil, nil)
+	if err != nil && !IsNotFound(err) {
+		return fmt.Errorf("reset compatibility of %s: %w", subject, err)
+	}
+	return nil
+}
+
+func (c *restClient) CheckCompatibility(ctx context.Context, schema models.SchemaRegistry) (models.CompatibilityResult, error) {
+	path := "/compatibility" + subjectPath(schema.Subject, "versions", "latest") + "?verbose=true"
+
+	var resp compatibilityResponse
+	err := c.do(ctx, http.MethodPost, path, newSchemaRequest(schema), &resp)
+	if IsNotFound(err) {
+		return models.CompatibilityResult{
+			Compatible: true,
+			Messages:   []string{"subject has no versions yet, nothing to check against"},
+		}, nil
+	}
+	if err != nil {
+		return models.CompatibilityResult{}, fmt.Errorf("check compatibility of %s: %w", schema.Subject, err)
+	}
+	return models.CompatibilityResult{Compatible: resp.IsCompatible, Messages: resp.Messages}, nil
+}
+
+func (c *restClient) RegisterSchema(ctx context.Context, schema models.SchemaRegistry) (int, error) {
+	var resp registerResponse
+	if err := c.do(ctx, http.MethodPost, subjectPath(schema.Subject, "versions"), newSchemaRequest(schema), &resp); err != nil {
+		return 0, fmt.Errorf("register schema for %s: %w", schema.Subject, err)
+	}
+	return resp.ID, nil
+}
+
+func (c *restClient) DeleteSubject(ctx context.Context, subject string, permanent bool) error {
+	if err := c.softThenHardDelete(ctx, subjectPath(subject), permanent); err != nil {
+		return fmt.Errorf("delete subject %s: %w", subject, err)
+	}
+	return nil
+}
+
+func (c *restClient) DeleteVersion(ctx context.Context, subject string, version int, permanent bool) error {
+	if err := c.softThenHardDelete(ctx, subjectPath(subject, "versions", strconv.Itoa(version)), permanent); err != nil {
+		return fmt.Errorf("delete %s version %d: %w", subject, version, err)
+	}
+	return nil
+}
+
+// softThenHardDelete soft deletes path and, if permanent, deletes it for
+// good, which the registry only allows once it is soft deleted
+func (c *restClient) softThenHardDelete(ctx context.Context, path string, permanent bool) error {
+	err := c.do(ctx, http.MethodDelete, path, nil, nil)
+	if !permanent {
+		return err
+	}
+	// already soft deleted, which is all the permanent delete needs
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	return c.do(ctx, http.MethodDelete, path+"?permanent=true", nil, nil)
+}
+
+func configPath(subject string) string {
+	if subject == "" {
+		return "/config"
+	}
+	return "/config/" + url.PathEscape(subject)
+}
+
+func describeSubject(subject string) string {
+	if subject == "" {
+		return "the registry"
+	}
+	return subject
+}
+
+// subjectPath builds /subjects/{subject}/... with the subject escaped, since
+// subject names may contain characters such as '/'
+func subjectPath(subject string, parts ...string) string {
+	path := "/subjects/" + url.PathEscape(subject)
+	for _, p := range parts {
+		path += "/" + p
+	}
+	return path
+}
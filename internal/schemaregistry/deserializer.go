@@ -0,0 +1,194 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// wireHeaderSize is the magic byte followed by the big-endian schema ID that
+// Confluent serializers prefix every key and value with
+const wireHeaderSize = 5
+
+const wireMagicByte = 0
+
+// retryInterval is how long a schema that failed to load for reasons other
+// than not existing is left alone, so an unreachable registry doesn't slow
+// down every record
+const retryInterval = 30 * time.Second
+
+var errTruncated = errors.New("payload is truncated")
+
+// IsWireFormat reports whether data starts with the Confluent wire format
+// header. Plain text never does, as it would start with a NUL byte.
+func IsWireFormat(data []byte) bool {
+	return len(data) >= wireHeaderSize && data[0] == wireMagicByte
+}
+
+// payloadDecoder turns the payload following the wire format header into a
+// value encoding/json renders
+type payloadDecoder interface {
+	decode(payload []byte) (any, error)
+}
+
+// cachedDecoder remembers schemas that cannot be used as well, so records
+// written with them don't fetch the schema again. Failed requests are
+// retried once retryAt has passed.
+type cachedDecoder struct {
+	decoder payloadDecoder
+	err     error
+	retryAt time.Time
+}
+
+// Deserializer decodes keys and values written by Confluent serializers into
+// JSON, fetching their schemas from the registry. Schemas are cached per
+// topic by ID.
+type Deserializer struct {
+	client Client
+	mu     sync.Mutex
+	topics map[string]map[int]cachedDecoder
+}
+
+func NewDeserializer(client Client) *Deserializer {
+	return &Deserializer{
+		client: client,
+		topics: make(map[string]map[int]cachedDecoder),
+	}
+}
+
+// Decode renders data, a key or value of topic in the wire format, as compact
+// JSON
+func (d *Deserializer) Decode(ctx context.Context, topic string, data []byte) (string, error) {
+	if !IsWireFormat(data) {
+		return "", fmt.Errorf("not in the schema registry wire format")
+	}
+	id := int(binary.BigEndian.Uint32(data[1:wireHeaderSize]))
+
+	decoder, err := d.decoder(ctx, topic, id)
+	if err != nil {
+		return "", err
+	}
+	value, err := decoder.decode(data[wireHeaderSize:])
+	if err != nil {
+		return "", fmt.Errorf("decode with schema %d: %w", id, err)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("decode with schema %d: %w", id, err)
+	}
+	return string(encoded), nil
+}
+
+func (d *Deserializer) decoder(ctx context.Context, topic string, id int) (payloadDecoder, error) {
+	d.mu.Lock()
+	cached, ok := d.topics[topic][id]
+	d.mu.Unlock()
+	if ok && (cached.retryAt.IsZero() || time.Now().Before(cached.retryAt)) {
+		return cached.decoder, cached.err
+	}
+
+	cached = cachedDecoder{}
+	schema, err := d.client.GetSchemaByID(ctx, id)
+	if ctx.Err() != nil {
+		// the caller gave up, which says nothing about the schema
+		return nil, err
+	}
+	switch {
+	case err == nil:
+		cached.decoder, err = newPayloadDecoder(schema.Type, schema.Schema)
+		if err != nil {
+			err = fmt.Errorf("schema %d: %w", id, err)
+		}
+	case !IsNotFound(err):
+		cached.retryAt = time.Now().Add(retryInterval)
+	}
+	cached.err = err
+
+	d.mu.Lock()
+	if d.topics[topic] == nil {
+		d.topics[topic] = make(map[int]cachedDecoder)
+	}
+	d.topics[topic][id] = cached
+	d.mu.Unlock()
+	return cached.decoder, cached.err
+}
+
+func newPayloadDecoder(schemaType, schema string) (payloadDecoder, error) {
+	switch schemaType {
+	case SchemaTypeAvro:
+		return newAvroDecoder(schema)
+	case SchemaTypeProtobuf:
+		return newProtobufDecoder(schema)
+	case SchemaTypeJSON:
+		return jsonDecoder{}, nil
+	}
+	return nil, fmt.Errorf("unsupported schema type %s", schemaType)
+}
+
+// jsonDecoder passes JSON Schema payloads through, they are plain JSON
+type jsonDecoder struct{}
+
+func (jsonDecoder) decode(payload []byte) (any, error) {
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("payload is not valid JSON")
+	}
+	return json.RawMessage(payload), nil
+}
+
+// orderedObject is a JSON object keeping its members in schema order, where
+// a map would sort them by name
+type orderedObject []objectMember
+
+type objectMember struct {
+	name  string
+	value any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// set replaces the member called name, or appends it
+func (o *orderedObject) set(name string, value any) {
+	for i := range *o {
+		if (*o)[i].name == name {
+			(*o)[i].value = value
+			return
+		}
+	}
+	*o = append(*o, objectMember{name: name, value: value})
+}
+
+// get returns the value of the member called name
+func (o orderedObject) get(name string) (any, bool) {
+	for _, m := range o {
+		if m.name == name {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,369 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jurabek/lazykafka/internal/models"
+)
+
+const (
+	SchemaTypeJSON     = "JSON"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+// schemaField is a named element of a schema flattened for comparison.
+// Fields sharing a non-empty identity under different paths are the same
+// field renamed, e.g. a Protobuf field keeping its number.
+type schemaField struct {
+	path     string
+	desc     string
+	identity string
+}
+
+// Diff returns the structural changes from oldSchema to newSchema, both of
+// schemaType
+func Diff(schemaType, oldSchema, newSchema string) ([]models.SchemaChange, error) {
+	oldFields, err := flattenSchema(schemaType, oldSchema)
+	if err != nil {
+		return nil, fmt.Errorf("parse old schema: %w", err)
+	}
+	newFields, err := flattenSchema(schemaType, newSchema)
+	if err != nil {
+		return nil, fmt.Errorf("parse new schema: %w", err)
+	}
+	return compareFields(oldFields, newFields), nil
+}
+
+// Validate checks that schema parses as schemaType before it is sent to the
+// registry
+func Validate(schemaType, schema string) error {
+	_, err := flattenSchema(schemaType, schema)
+	return err
+}
+
+func flattenSchema(schemaType, schema string) ([]schemaField, error) {
+	switch schemaType {
+	case SchemaTypeAvro, "":
+		return avroFields(schema)
+	case SchemaTypeJSON:
+		return jsonSchemaFields(schema)
+	case SchemaTypeProtobuf:
+		return protobufFields(schema)
+	}
+	return nil, fmt.Errorf("unsupported schema type %s", schemaType)
+}
+
+func compareFields(oldFields, newFields []schemaField) []models.SchemaChange {
+	oldByPath := make(map[string]schemaField, len(oldFields))
+	for _, f := range oldFields {
+		oldByPath[f.path] = f
+	}
+	newByPath := make(map[string]schemaField, len(newFields))
+	for _, f := range newFields {
+		newByPath[f.path] = f
+	}
+
+	var changes []models.SchemaChange
+	var removed []schemaField
+	added := make(map[string]schemaField)
+	for _, f := range newFields {
+		if _, ok := oldByPath[f.path]; !ok {
+			added[f.path] = f
+		}
+	}
+
+	for _, f := range oldFields {
+		n, ok := newByPath[f.path]
+		if !ok {
+			removed = append(removed, f)
+			continue
+		}
+		if n.desc != f.desc {
+			changes = append(changes, models.SchemaChange{
+				Kind: models.SchemaFieldChanged,
+				Path: f.path,
+				Old:  f.desc,
+				New:  n.desc,
+			})
+		}
+	}
+
+	for _, f := range removed {
+		if renamed, ok := findRenamed(f, added); ok {
+			delete(added, renamed.path)
+			changes = append(changes, models.SchemaChange{
+				Kind:      models.SchemaFieldRenamed,
+				Path:      f.path,
+				Old:       f.desc,
+				New:       renamed.desc,
+				RenamedTo: renamed.path,
+			})
+			continue
+		}
+		changes = append(changes, models.SchemaChange{
+			Kind: models.SchemaFieldRemoved,
+			Path: f.path,
+			Old:  f.desc,
+		})
+	}
+
+	for _, f := range added {
+		changes = append(changes, models.SchemaChange{
+			Kind: models.SchemaFieldAdded,
+			Path: f.path,
+			New:  f.desc,
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func findRenamed(f schemaField, added map[string]schemaField) (schemaField, bool) {
+	if f.identity == "" {
+		return schemaField{}, false
+	}
+	for _, a := range added {
+		if a.identity == f.identity {
+			return a, true
+		}
+	}
+	return schemaField{}, false
+}
+
+// avroFields flattens every named record and enum reachable from the schema
+func avroFields(schema string) ([]schemaField, error) {
+	var root any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, err
+	}
+	w := avroWalker{seen: make(map[string]bool)}
+	w.walk(root, "")
+	return w.fields, nil
+}
+
+type avroWalker struct {
+	fields []schemaField
+	seen   map[string]bool
+}
+
+func (w *avroWalker) walk(t any, namespace string) {
+	switch t := t.(type) {
+	case []any:
+		for _, branch := range t {
+			w.walk(branch, namespace)
+		}
+	case map[string]any:
+		kind, _ := t["type"].(string)
+		switch kind {
+		case "record", "error":
+			name, ns := avroName(t, namespace)
+			if w.seen[name] {
+				return
+			}
+			w.seen[name] = true
+			fields, _ := t["fields"].([]any)
+			for _, raw := range fields {
+				field, ok := raw.(map[string]any)
+				if !ok {
+					continue
+				}
+				fieldName, _ := field["name"].(string)
+				desc := avroTypeName(field["type"])
+				if def, ok := field["default"]; ok {
+					encoded, _ := json.Marshal(def)
+					desc += " = " + string(encoded)
+				}
+				w.fields = append(w.fields, schemaField{path: shortName(name) + "." + fieldName, desc: desc})
+				w.walk(field["type"], ns)
+			}
+		case "enum":
+			name, _ := avroName(t, namespace)
+			if w.seen[name] {
+				return
+			}
+			w.seen[name] = true
+			w.fields = append(w.fields, schemaField{path: shortName(name), desc: "enum " + joinAny(t["symbols"])})
+		case "array":
+			w.walk(t["items"], namespace)
+		case "map":
+			w.walk(t["values"], namespace)
+		}
+	}
+}
+
+// avroName returns the full name of a named type and the namespace its
+// children inherit
+func avroName(t map[string]any, namespace string) (string, string) {
+	name, _ := t["name"].(string)
+	if ns, ok := t["namespace"].(string); ok {
+		namespace = ns
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name, name[:i]
+	}
+	if namespace == "" {
+		return name, namespace
+	}
+	return namespace + "." + name, namespace
+}
+
+// shortName drops the namespace of a full name
+func shortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func avroTypeName(t any) string {
+	switch t := t.(type) {
+	case string:
+		return t
+	case []any:
+		names := make([]string, len(t))
+		for i, branch := range t {
+			names[i] = avroTypeName(branch)
+		}
+		return "[" + strings.Join(names, ", ") + "]"
+	case map[string]any:
+		switch kind, _ := t["type"].(string); kind {
+		case "record", "error", "enum", "fixed":
+			name, _ := t["name"].(string)
+			return name
+		case "array":
+			return "array<" + avroTypeName(t["items"]) + ">"
+		case "map":
+			return "map<" + avroTypeName(t["values"]) + ">"
+		default:
+			name := avroTypeName(t["type"])
+			if logical, ok := t["logicalType"].(string); ok {
+				name += " (" + logical + ")"
+			}
+			return name
+		}
+	}
+	return "?"
+}
+
+// jsonSchemaFields flattens the properties of a JSON Schema, including those
+// of nested objects, array items and definitions
+func jsonSchemaFields(schema string) ([]schemaField, error) {
+	var root map[string]any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, err
+	}
+	w := jsonSchemaWalker{byPath: make(map[string]int)}
+	w.walk(root, "")
+	for _, key := range []string{"definitions", "$defs"} {
+		defs, _ := root[key].(map[string]any)
+		for _, name := range sortedKeys(defs) {
+			if def, ok := defs[name].(map[string]any); ok {
+				w.walk(def, key+"."+name)
+			}
+		}
+	}
+	return w.fields, nil
+}
+
+type jsonSchemaWalker struct {
+	fields []schemaField
+	byPath map[string]int
+}
+
+func (w *jsonSchemaWalker) add(f schemaField) {
+	if i, ok := w.byPath[f.path]; ok {
+		w.fields[i] = f
+		return
+	}
+	w.byPath[f.path] = len(w.fields)
+	w.fields = append(w.fields, f)
+}
+
+func (w *jsonSchemaWalker) walk(node map[string]any, path string) {
+	required := make(map[string]bool)
+	if list, ok := node["required"].([]any); ok {
+		for _, r := range list {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	props, _ := node["properties"].(map[string]any)
+	for _, name := range sortedKeys(props) {
+		prop, ok := props[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		propPath := name
+		if path != "" {
+			propPath = path + "." + name
+		}
+		desc := jsonSchemaTypeName(prop)
+		if required[name] {
+			desc += " (required)"
+		}
+		w.add(schemaField{path: propPath, desc: desc})
+		w.walk(prop, propPath)
+	}
+
+	if items, ok := node["items"].(map[string]any); ok {
+		w.walk(items, path+"[]")
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		list, _ := node[key].([]any)
+		for _, sub := range list {
+			if m, ok := sub.(map[string]any); ok {
+				w.walk(m, path)
+			}
+		}
+	}
+}
+
+func jsonSchemaTypeName(node map[string]any) string {
+	if ref, ok := node["$ref"].(string); ok {
+		return "$ref " + ref
+	}
+	if enum, ok := node["enum"]; ok {
+		return "enum " + joinAny(enum)
+	}
+
+	var name string
+	switch t := node["type"].(type) {
+	case string:
+		name = t
+	case []any:
+		name = strings.Trim(joinAny(t), "[]")
+		name = strings.ReplaceAll(name, ", ", "|")
+	default:
+		name = "any"
+	}
+	if items, ok := node["items"].(map[string]any); ok && name == "array" {
+		name = "array<" + jsonSchemaTypeName(items) + ">"
+	}
+	if format, ok := node["format"].(string); ok {
+		name += " (" + format + ")"
+	}
+	return name
+}
+
+func joinAny(v any) string {
+	list, _ := v.([]any)
+	parts := make([]string, len(list))
+	for i, item := range list {
+		parts[i] = fmt.Sprint(item)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	contentType     = "application/vnd.schemaregistry.v1+json"
+	maxResponseSize = 16 << 20
+)
+
+// Error codes returned by the registry next to the HTTP status
+const (
+	ErrCodeSubjectNotFound = 40401
+	ErrCodeVersionNotFound = 40402
+	ErrCodeSchemaNotFound  = 40403
+)
+
+// Error is an error response of the registry
+type Error struct {
+	StatusCode int
+	Code       int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("schema registry returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("schema registry returned %d: %s", e.Code, e.Message)
+}
+
+// IsNotFound reports whether err means the requested subject, version or
+// schema does not exist
+func IsNotFound(err error) bool {
+	var regErr *Error
+	return errors.As(err, &regErr) && regErr.StatusCode == http.StatusNotFound
+}
+
+// do sends a request with body encoded as JSON and decodes the response into
+// out. A nil body sends no payload, a nil out discards the response.
+func (c *restClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", contentType)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case c.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		regErr := &Error{StatusCode: resp.StatusCode}
+		_ = json.Unmarshal(data, regErr)
+		return regErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
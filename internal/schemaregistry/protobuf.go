@@ -0,0 +1,799 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// protoFile is the subset of a .proto schema needed to compare versions and
+// decode messages: messages, their fields and enums. Names are fully
+// qualified with the package.
+type protoFile struct {
+	pkg      string
+	messages []*protoMessage
+	enums    []*protoEnum
+}
+
+type protoMessage struct {
+	name   string
+	fields []protoField
+}
+
+type protoField struct {
+	name     string
+	number   int
+	label    string
+	typeName string
+	mapKey   string
+	mapValue string
+	oneof    string
+}
+
+type protoEnum struct {
+	name   string
+	values []protoEnumValue
+}
+
+type protoEnumValue struct {
+	name   string
+	number int
+}
+
+func (f protoField) isMap() bool {
+	return f.mapKey != ""
+}
+
+func (f protoField) describe() string {
+	typeName := f.typeName
+	if f.isMap() {
+		typeName = "map<" + f.mapKey + ", " + f.mapValue + ">"
+	}
+	if f.label != "" {
+		typeName = f.label + " " + typeName
+	}
+	desc := fmt.Sprintf("%s = %d", typeName, f.number)
+	if f.oneof != "" {
+		desc += " (oneof " + f.oneof + ")"
+	}
+	return desc
+}
+
+// protobufFields flattens messages and enums. Fields are identified by their
+// number, so a field renamed without changing its number is reported as such.
+func protobufFields(schema string) ([]schemaField, error) {
+	file, err := parseProto(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []schemaField
+	for _, msg := range file.messages {
+		path := file.localName(msg.name)
+		fields = append(fields, schemaField{path: path, desc: "message"})
+		for _, f := range msg.fields {
+			fields = append(fields, schemaField{
+				path:     path + "." + f.name,
+				desc:     f.describe(),
+				identity: msg.name + "#" + strconv.Itoa(f.number),
+			})
+		}
+	}
+	for _, enum := range file.enums {
+		path := file.localName(enum.name)
+		fields = append(fields, schemaField{path: path, desc: "enum"})
+		for _, v := range enum.values {
+			fields = append(fields, schemaField{
+				path:     path + "." + v.name,
+				desc:     "= " + strconv.Itoa(v.number),
+				identity: enum.name + "#" + strconv.Itoa(v.number),
+			})
+		}
+	}
+	return fields, nil
+}
+
+// localName drops the package from a fully qualified name
+func (f *protoFile) localName(name string) string {
+	if f.pkg == "" {
+		return name
+	}
+	return strings.TrimPrefix(name, f.pkg+".")
+}
+
+type protoParser struct {
+	tokens []string
+	pos    int
+	file   *protoFile
+}
+
+func parseProto(schema string) (*protoFile, error) {
+	tokens, err := tokenizeProto(schema)
+	if err != nil {
+		return nil, err
+	}
+	p := &protoParser{tokens: tokens, file: &protoFile{}}
+
+	for !p.done() {
+		switch tok := p.next(); tok {
+		case "syntax", "edition", "import", "option":
+			p.skipStatement()
+		case "package":
+			p.file.pkg = p.next()
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case "message":
+			if err := p.parseMessage(p.file.pkg); err != nil {
+				return nil, err
+			}
+		case "enum":
+			if err := p.parseEnum(p.file.pkg); err != nil {
+				return nil, err
+			}
+		case "service", "extend":
+			p.skipBlock()
+		case ";":
+		default:
+			return nil, fmt.Errorf("unexpected %q in protobuf schema", tok)
+		}
+	}
+	return p.file, nil
+}
+
+func (p *protoParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *protoParser) next() string {
+	if p.done() {
+		return ""
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok
+}
+
+func (p *protoParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *protoParser) expect(want string) error {
+	if tok := p.next(); tok != want {
+		return fmt.Errorf("expected %q, got %q in protobuf schema", want, tok)
+	}
+	return nil
+}
+
+// skipStatement skips up to and including the ';' ending the statement,
+// along with any option values in braces
+func (p *protoParser) skipStatement() {
+	depth := 0
+	for !p.done() {
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		case ";":
+			if depth <= 0 {
+				return
+			}
+		}
+	}
+}
+
+// skipBlock skips a declaration up to and including its closing brace
+func (p *protoParser) skipBlock() {
+	depth := 0
+	for !p.done() {
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *protoParser) parseMessage(scope string) error {
+	msg := &protoMessage{name: qualify(scope, p.next())}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	p.file.messages = append(p.file.messages, msg)
+
+	for {
+		switch tok := p.peek(); tok {
+		case "":
+			return fmt.Errorf("unterminated message %s", msg.name)
+		case "}":
+			p.next()
+			return nil
+		case "message":
+			p.next()
+			if err := p.parseMessage(msg.name); err != nil {
+				return err
+			}
+		case "enum":
+			p.next()
+			if err := p.parseEnum(msg.name); err != nil {
+				return err
+			}
+		case "oneof":
+			p.next()
+			if err := p.parseOneof(msg); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions":
+			p.skipStatement()
+		case "extend":
+			p.skipBlock()
+		case ";":
+			p.next()
+		default:
+			if err := p.parseField(msg, ""); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *protoParser) parseOneof(msg *protoMessage) error {
+	name := p.next()
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		switch p.peek() {
+		case "":
+			return fmt.Errorf("unterminated oneof %s", name)
+		case "}":
+			p.next()
+			return nil
+		case "option":
+			p.skipStatement()
+		case ";":
+			p.next()
+		default:
+			if err := p.parseField(msg, name); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *protoParser) parseField(msg *protoMessage, oneof string) error {
+	field := protoField{oneof: oneof}
+
+	tok := p.next()
+	if tok == "repeated" || tok == "optional" || tok == "required" {
+		field.label = tok
+		tok = p.next()
+	}
+	if tok == "group" {
+		return fmt.Errorf("groups are not supported in message %s", msg.name)
+	}
+
+	if tok == "map" {
+		if err := p.expect("<"); err != nil {
+			return err
+		}
+		field.mapKey = p.next()
+		if err := p.expect(","); err != nil {
+			return err
+		}
+		field.mapValue = p.next()
+		if err := p.expect(">"); err != nil {
+			return err
+		}
+		field.typeName = "map"
+	} else {
+		field.typeName = tok
+	}
+
+	field.name = p.next()
+	if err := p.expect("="); err != nil {
+		return err
+	}
+	number, err := strconv.Atoi(p.next())
+	if err != nil {
+		return fmt.Errorf("invalid number of field %s.%s", msg.name, field.name)
+	}
+	field.number = number
+
+	// field options such as [deprecated = true] end with the statement
+	p.skipStatement()
+	msg.fields = append(msg.fields, field)
+	return nil
+}
+
+func (p *protoParser) parseEnum(scope string) error {
+	enum := &protoEnum{name: qualify(scope, p.next())}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	p.file.enums = append(p.file.enums, enum)
+
+	for {
+		switch tok := p.next(); tok {
+		case "":
+			return fmt.Errorf("unterminated enum %s", enum.name)
+		case "}":
+			return nil
+		case "option", "reserved":
+			p.skipStatement()
+		case ";":
+		default:
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			number, err := strconv.Atoi(p.next())
+			if err != nil {
+				return fmt.Errorf("invalid number of enum value %s.%s", enum.name, tok)
+			}
+			enum.values = append(enum.values, protoEnumValue{name: tok, number: number})
+			p.skipStatement()
+		}
+	}
+}
+
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// tokenizeProto splits a .proto schema into identifiers, numbers, strings
+// and punctuation, dropping comments
+func tokenizeProto(schema string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(schema); {
+		c := schema[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(schema[i:], "//"):
+			end := strings.IndexByte(schema[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end
+		case strings.HasPrefix(schema[i:], "/*"):
+			end := strings.Index(schema[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment in protobuf schema")
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			start := i
+			for i++; i < len(schema) && schema[i] != c; i++ {
+				if schema[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(schema) {
+				return nil, fmt.Errorf("unterminated string in protobuf schema")
+			}
+			i++
+			tokens = append(tokens, schema[start:i])
+		case isProtoIdentByte(c) || c == '-' || c == '+':
+			start := i
+			for i++; i < len(schema) && isProtoIdentByte(schema[i]); i++ {
+			}
+			tokens = append(tokens, schema[start:i])
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isProtoIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+// protobufDecoder reads Protobuf messages written by the Confluent
+// serializer, which puts the path to the message type in the schema between
+// the wire format header and the message
+type protobufDecoder struct {
+	file     *protoFile
+	messages map[string]*protoMessage
+	enums    map[string]*protoEnum
+}
+
+func newProtobufDecoder(schema string) (*protobufDecoder, error) {
+	file, err := parseProto(schema)
+	if err != nil {
+		return nil, err
+	}
+	d := &protobufDecoder{
+		file:     file,
+		messages: make(map[string]*protoMessage, len(file.messages)),
+		enums:    make(map[string]*protoEnum, len(file.enums)),
+	}
+	for _, msg := range file.messages {
+		d.messages[msg.name] = msg
+	}
+	for _, enum := range file.enums {
+		d.enums[enum.name] = enum
+	}
+	return d, nil
+}
+
+func (d *protobufDecoder) decode(payload []byte) (any, error) {
+	r := &protoReader{buf: payload}
+	indexes, err := r.messageIndexes()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := d.messageAt(indexes)
+	if err != nil {
+		return nil, err
+	}
+	return d.readMessage(r.buf, msg)
+}
+
+// messageAt finds the message type the indexes point at: the first is the
+// position among the top level messages, each further one among the
+// messages nested in the previous
+func (d *protobufDecoder) messageAt(indexes []int) (*protoMessage, error) {
+	scope := d.file.pkg
+	var msg *protoMessage
+	for _, index := range indexes {
+		nested := d.file.nestedMessages(scope)
+		if index < 0 || index >= len(nested) {
+			return nil, fmt.Errorf("message index %d out of range in %s", index, describeScope(scope))
+		}
+		msg = nested[index]
+		scope = msg.name
+	}
+	return msg, nil
+}
+
+func describeScope(scope string) string {
+	if scope == "" {
+		return "schema"
+	}
+	return scope
+}
+
+// nestedMessages returns the messages declared directly in scope, a package
+// or message name, in declaration order
+func (f *protoFile) nestedMessages(scope string) []*protoMessage {
+	var nested []*protoMessage
+	for _, msg := range f.messages {
+		name := msg.name
+		if scope != "" {
+			if !strings.HasPrefix(name, scope+".") {
+				continue
+			}
+			name = name[len(scope)+1:]
+		}
+		if !strings.Contains(name, ".") {
+			nested = append(nested, msg)
+		}
+	}
+	return nested
+}
+
+// resolve looks a type name up the way protoc does, from the innermost scope
+// outwards. Types it cannot find, such as imported ones, are nil.
+func (d *protobufDecoder) resolve(scope, name string) (*protoMessage, *protoEnum) {
+	if strings.HasPrefix(name, ".") {
+		name = name[1:]
+		return d.messages[name], d.enums[name]
+	}
+	for {
+		full := qualify(scope, name)
+		if msg, ok := d.messages[full]; ok {
+			return msg, nil
+		}
+		if enum, ok := d.enums[full]; ok {
+			return nil, enum
+		}
+		if scope == "" {
+			return nil, nil
+		}
+		scope = scope[:max(strings.LastIndex(scope, "."), 0)]
+	}
+}
+
+// readMessage decodes the fields of msg in buf. Fields missing from the
+// schema are skipped.
+func (d *protobufDecoder) readMessage(buf []byte, msg *protoMessage) (orderedObject, error) {
+	r := &protoReader{buf: buf}
+	obj := orderedObject{}
+	for len(r.buf) > 0 {
+		key, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		number, wireType := int(key>>3), int(key&7)
+
+		field := msg.field(number)
+		if field == nil {
+			if err := r.skip(wireType); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch {
+		case field.isMap():
+			entry, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			key, value, err := d.readMapEntry(entry, msg, field)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.name, err)
+			}
+			entries, _ := obj.get(field.name)
+			m, _ := entries.(orderedObject)
+			m.set(key, value)
+			obj.set(field.name, m)
+		case field.label == "repeated":
+			values, err := d.readRepeated(r, wireType, msg, field)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.name, err)
+			}
+			existing, _ := obj.get(field.name)
+			items, _ := existing.([]any)
+			obj.set(field.name, append(items, values...))
+		default:
+			value, err := d.readValue(r, wireType, msg, field.typeName)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.name, err)
+			}
+			obj.set(field.name, value)
+		}
+	}
+	return obj, nil
+}
+
+func (d *protobufDecoder) readMapEntry(buf []byte, msg *protoMessage, field *protoField) (string, any, error) {
+	entry := &protoMessage{
+		name: msg.name,
+		fields: []protoField{
+			{name: "key", number: 1, typeName: field.mapKey},
+			{name: "value", number: 2, typeName: field.mapValue},
+		},
+	}
+	obj, err := d.readMessage(buf, entry)
+	if err != nil {
+		return "", nil, err
+	}
+	key, _ := obj.get("key")
+	value, _ := obj.get("value")
+	if key == nil {
+		key = ""
+	}
+	return fmt.Sprint(key), value, nil
+}
+
+// readRepeated reads one element of a repeated field, or all of them when
+// scalars are packed into a single length delimited value
+func (d *protobufDecoder) readRepeated(r *protoReader, wireType int, msg *protoMessage, field *protoField) ([]any, error) {
+	elemWireType := d.wireType(msg, field.typeName)
+	if wireType != protoWireBytes || elemWireType == protoWireBytes {
+		value, err := d.readValue(r, wireType, msg, field.typeName)
+		return []any{value}, err
+	}
+
+	packed, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	pr := &protoReader{buf: packed}
+	var values []any
+	for len(pr.buf) > 0 {
+		value, err := d.readValue(pr, elemWireType, msg, field.typeName)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (d *protobufDecoder) readValue(r *protoReader, wireType int, msg *protoMessage, typeName string) (any, error) {
+	if want := d.wireType(msg, typeName); wireType != want {
+		return nil, fmt.Errorf("wire type %d does not match %s", wireType, typeName)
+	}
+
+	switch wireType {
+	case protoWireVarint:
+		v, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		switch typeName {
+		case "int32":
+			return int32(v), nil
+		case "int64":
+			return int64(v), nil
+		case "uint32", "uint64":
+			return v, nil
+		case "sint32", "sint64":
+			return int64(v>>1) ^ -int64(v&1), nil
+		case "bool":
+			return v != 0, nil
+		}
+		if _, enum := d.resolve(msg.name, typeName); enum != nil {
+			for _, value := range enum.values {
+				if uint64(int32(value.number)) == v {
+					return value.name, nil
+				}
+			}
+		}
+		return int32(v), nil
+	case protoWireFixed64:
+		b, err := r.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint64(b)
+		switch typeName {
+		case "double":
+			return jsonFloat(math.Float64frombits(v)), nil
+		case "sfixed64":
+			return int64(v), nil
+		}
+		return v, nil
+	case protoWireFixed32:
+		b, err := r.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint32(b)
+		switch typeName {
+		case "float":
+			return jsonFloat(float64(math.Float32frombits(v))), nil
+		case "sfixed32":
+			return int32(v), nil
+		}
+		return v, nil
+	}
+
+	b, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	switch typeName {
+	case "string":
+		return string(b), nil
+	case "bytes":
+		return b, nil
+	}
+	if nested, _ := d.resolve(msg.name, typeName); nested != nil {
+		return d.readMessage(b, nested)
+	}
+	// imported types are not part of the schema, keep their raw bytes
+	return b, nil
+}
+
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// wireType returns how values of typeName are encoded
+func (d *protobufDecoder) wireType(msg *protoMessage, typeName string) int {
+	switch typeName {
+	case "int32", "int64", "uint32", "uint64", "sint32", "sint64", "bool":
+		return protoWireVarint
+	case "fixed64", "sfixed64", "double":
+		return protoWireFixed64
+	case "fixed32", "sfixed32", "float":
+		return protoWireFixed32
+	case "string", "bytes":
+		return protoWireBytes
+	}
+	if _, enum := d.resolve(msg.name, typeName); enum != nil {
+		return protoWireVarint
+	}
+	return protoWireBytes
+}
+
+func (m *protoMessage) field(number int) *protoField {
+	for i := range m.fields {
+		if m.fields[i].number == number {
+			return &m.fields[i]
+		}
+	}
+	return nil
+}
+
+type protoReader struct {
+	buf []byte
+}
+
+func (r *protoReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf)
+	if n == 0 {
+		return 0, errTruncated
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	r.buf = r.buf[n:]
+	return v, nil
+}
+
+func (r *protoReader) fixed(n int) ([]byte, error) {
+	if n > len(r.buf) {
+		return nil, errTruncated
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b, nil
+}
+
+// bytes reads a length delimited value
+func (r *protoReader) bytes() ([]byte, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)) {
+		return nil, errTruncated
+	}
+	return r.fixed(int(n))
+}
+
+func (r *protoReader) skip(wireType int) error {
+	var err error
+	switch wireType {
+	case protoWireVarint:
+		_, err = r.uvarint()
+	case protoWireFixed64:
+		_, err = r.fixed(8)
+	case protoWireBytes:
+		_, err = r.bytes()
+	case protoWireFixed32:
+		_, err = r.fixed(4)
+	default:
+		err = fmt.Errorf("unsupported wire type %d", wireType)
+	}
+	return err
+}
+
+// messageIndexes reads the zigzag encoded count and message indexes. A zero
+// count is shorthand for the first top level message.
+func (r *protoReader) messageIndexes() ([]int, error) {
+	count, n := binary.Varint(r.buf)
+	if n <= 0 {
+		return nil, errTruncated
+	}
+	r.buf = r.buf[n:]
+	if count == 0 {
+		return []int{0}, nil
+	}
+	if count < 0 || count > int64(len(r.buf)) {
+		return nil, fmt.Errorf("invalid message index count %d", count)
+	}
+
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(r.buf)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		r.buf = r.buf[n:]
+		indexes[i] = int(index)
+	}
+	return indexes, nil
+}
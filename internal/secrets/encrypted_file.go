@@ -0,0 +1,212 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv unlocks the encrypted secret file without prompting
+const PassphraseEnv = "LAZYKAFKA_SECRETS_PASSPHRASE"
+
+var (
+	ErrLocked        = errors.New("secret file is locked")
+	ErrBadPassphrase = errors.New("wrong passphrase or corrupted secret file")
+)
+
+var fileMagic = []byte("lazykafka-secrets-v1\n")
+
+const (
+	saltSize = 16
+	// scrypt parameters for interactive use, as recommended by the scrypt paper
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// EncryptedFileStore keeps credentials in a local file encrypted with a key
+// derived from a passphrase using scrypt and XChaCha20-Poly1305
+type EncryptedFileStore struct {
+	mu         sync.Mutex
+	path       string
+	passphrase string
+	unlocked   bool
+}
+
+func NewEncryptedFileStore(path string) *EncryptedFileStore {
+	store := &EncryptedFileStore{path: path}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		_ = store.Unlock(passphrase)
+	}
+	return store
+}
+
+func (s *EncryptedFileStore) Locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.unlocked
+}
+
+// Unlock checks passphrase against the existing file. When there is no file
+// yet, passphrase is used to create it on the first save.
+func (s *EncryptedFileStore) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.read(passphrase); err != nil {
+		return err
+	}
+	s.passphrase = passphrase
+	s.unlocked = true
+	return nil
+}
+
+func (s *EncryptedFileStore) SaveCredentials(brokerName, username, password string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.unlocked {
+		return ErrLocked
+	}
+	all, err := s.read(s.passphrase)
+	if err != nil {
+		return err
+	}
+	all[brokerName] = credentials{Username: username, Password: password}
+	return s.write(all)
+}
+
+func (s *EncryptedFileStore) GetCredentials(brokerName string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.unlocked {
+		return "", "", ErrLocked
+	}
+	all, err := s.read(s.passphrase)
+	if err != nil {
+		return "", "", err
+	}
+	creds, ok := all[brokerName]
+	if !ok {
+		return "", "", ErrNotFound
+	}
+	return creds.Username, creds.Password, nil
+}
+
+func (s *EncryptedFileStore) DeleteCredentials(brokerName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.unlocked {
+		return ErrLocked
+	}
+	all, err := s.read(s.passphrase)
+	if err != nil {
+		return err
+	}
+	if _, ok := all[brokerName]; !ok {
+		return ErrNotFound
+	}
+	delete(all, brokerName)
+	return s.write(all)
+}
+
+func (s *EncryptedFileStore) read(passphrase string) (map[string]credentials, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]credentials{}, nil
+		}
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, fileMagic) {
+		return nil, fmt.Errorf("%s is not a lazykafka secret file", s.path)
+	}
+	data = data[len(fileMagic):]
+	if len(data) < saltSize+chacha20poly1305.NonceSizeX {
+		return nil, ErrBadPassphrase
+	}
+	salt := data[:saltSize]
+	nonce := data[saltSize : saltSize+chacha20poly1305.NonceSizeX]
+	ciphertext := data[saltSize+chacha20poly1305.NonceSizeX:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, fileMagic)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+
+	all := map[string]credentials{}
+	if err := json.Unmarshal(plaintext, &all); err != nil {
+		return nil, fmt.Errorf("unmarshal credentials: %w", err)
+	}
+	return all, nil
+}
+
+// write encrypts all with a fresh salt and nonce and replaces the file
+// atomically
+func (s *EncryptedFileStore) write(all map[string]credentials) error {
+	plaintext, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("marshal credentials: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(s.passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	out := append([]byte{}, fileMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = aead.Seal(out, nonce, plaintext, fileMagic)
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+// DefaultSecretFilePath returns the encrypted secret file next to brokers.json
+func DefaultSecretFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".lazykafka", "secrets.enc"), nil
+}
@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/zalando/go-keyring"
@@ -9,12 +10,22 @@ import (
 
 const serviceName = "lazykafka"
 
+// ErrNotFound is returned by every SecretStore when a broker has no stored
+// credentials
+var ErrNotFound = errors.New("no credentials stored for broker")
+
 type SecretStore interface {
 	SaveCredentials(brokerName, username, password string) error
 	GetCredentials(brokerName string) (username, password string, err error)
 	DeleteCredentials(brokerName string) error
 }
 
+// Unlocker is implemented by stores that need a passphrase before use
+type Unlocker interface {
+	Unlock(passphrase string) error
+	Locked() bool
+}
+
 type KeyringStore struct{}
 
 func NewKeyringStore() *KeyringStore {
@@ -40,6 +51,9 @@ func (k *KeyringStore) SaveCredentials(brokerName, username, password string) er
 
 func (k *KeyringStore) GetCredentials(brokerName string) (string, string, error) {
 	data, err := keyring.Get(serviceName, brokerName)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", "", ErrNotFound
+	}
 	if err != nil {
 		return "", "", err
 	}
@@ -51,5 +65,9 @@ func (k *KeyringStore) GetCredentials(brokerName string) (string, string, error)
 }
 
 func (k *KeyringStore) DeleteCredentials(brokerName string) error {
-	return keyring.Delete(serviceName, brokerName)
+	err := keyring.Delete(serviceName, brokerName)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
 }
@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	envPrefix = "env:"
+	cmdPrefix = "cmd:"
+
+	commandTimeout = 30 * time.Second
+)
+
+// IsReference reports whether value points at a secret instead of holding
+// one, e.g. env:KAFKA_PASS or cmd:pass show kafka/prod. References are not
+// secret and may be stored in brokers.json.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, envPrefix) || strings.HasPrefix(value, cmdPrefix)
+}
+
+// ResolveReference returns the secret a reference points at. Commands run
+// through the shell and their first output line is used, matching tools
+// like pass.
+func ResolveReference(ctx context.Context, ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, envPrefix):
+		name := strings.TrimSpace(strings.TrimPrefix(ref, envPrefix))
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(ref, cmdPrefix):
+		command := strings.TrimSpace(strings.TrimPrefix(ref, cmdPrefix))
+		if command == "" {
+			return "", errors.New("empty secret command")
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+		defer cancel()
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("secret command %q failed: %w: %s", command, err, msg)
+			}
+			return "", fmt.Errorf("secret command %q failed: %w", command, err)
+		}
+
+		value, _, _ := strings.Cut(string(out), "\n")
+		value = strings.TrimRight(value, "\r")
+		if value == "" {
+			return "", fmt.Errorf("secret command %q printed nothing", command)
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("not a secret reference: %q", ref)
+}
@@ -0,0 +1,48 @@
+// Package tlsconfig builds client TLS settings shared by the Kafka and
+// Schema Registry connections
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jurabek/lazykafka/internal/models"
+)
+
+// New builds client TLS settings from config. Without a CA file the system
+// roots are used.
+func New(config models.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(models.ExpandHome(config.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	if config.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(models.ExpandHome(config.CertFile), models.ExpandHome(config.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -58,6 +58,17 @@ func (h *keyBindingHandler) SetupKeyBindings(g *gocui.Gui) error {
 		}
 	}
 
+	// Detail views never take focus, so their commands are bound to the
+	// sidebar view they belong to
+	for idx, view := range h.layout.detailViews {
+		viewName := h.layout.sidebarViews[idx].GetViewModel().GetName()
+		bindings := view.GetViewModel().GetCommandBindings()
+
+		if err := h.bindViewCommands(g, viewName, bindings); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -120,6 +131,22 @@ func (h *keyBindingHandler) getGlobalBindings() []*types.Binding {
 			Description:  "new broker",
 			BlockOnPopup: true,
 		},
+		{
+			ViewName:     panelBrokers,
+			Key:          'E',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showEditBrokerPopup,
+			Description:  "edit broker",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelBrokers,
+			Key:          'd',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showDeleteBrokerPopup,
+			Description:  "delete broker",
+			BlockOnPopup: true,
+		},
 		{
 			ViewName:     panelTopics,
 			Key:          'n',
@@ -128,6 +155,110 @@ func (h *keyBindingHandler) getGlobalBindings() []*types.Binding {
 			Description:  "new topic",
 			BlockOnPopup: true,
 		},
+		{
+			ViewName:     panelTopics,
+			Key:          'd',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showDeleteTopicsPopup,
+			Description:  "delete marked or selected topics",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelTopics,
+			Key:          'a',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showAddPartitionsPopup,
+			Description:  "add partitions",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelTopics,
+			Key:          't',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showTruncatePopup,
+			Description:  "truncate topic",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelTopics,
+			Key:          'o',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showMessageQueryPopup,
+			Description:  "browse messages from",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelTopics,
+			Key:          'P',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showProducePopup,
+			Description:  "produce messages",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelTopics,
+			Key:          'c',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showEditTopicConfigPopup,
+			Description:  "edit topic config",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelConsumerGroups,
+			Key:          'r',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showResetOffsetsPopup,
+			Description:  "reset offsets",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelConsumerGroups,
+			Key:          'd',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showDeleteConsumerGroupPopup,
+			Description:  "delete consumer group",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelConsumerGroups,
+			Key:          'x',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showDeleteGroupOffsetsPopup,
+			Description:  "delete committed offsets for a topic",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelSchemaRegistry,
+			Key:          'n',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showRegisterSchemaPopup,
+			Description:  "register or check schema",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelSchemaRegistry,
+			Key:          'c',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showSubjectCompatibilityPopup,
+			Description:  "change subject compatibility",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelSchemaRegistry,
+			Key:          'C',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showGlobalCompatibilityPopup,
+			Description:  "change global compatibility",
+			BlockOnPopup: true,
+		},
+		{
+			ViewName:     panelSchemaRegistry,
+			Key:          'd',
+			Modifier:     gocui.ModNone,
+			Handler:      h.showDeleteSchemaPopup,
+			Description:  "delete subject or version",
+			BlockOnPopup: true,
+		},
 	}
 }
 
@@ -188,9 +319,114 @@ func (h *keyBindingHandler) showAddBrokerPopup() error {
 	return h.layout.ShowAddBrokerPopup()
 }
 
+func (h *keyBindingHandler) showEditBrokerPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowEditBrokerPopup()
+}
+
+func (h *keyBindingHandler) showDeleteBrokerPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowDeleteBrokerPopup()
+}
+
 func (h *keyBindingHandler) showAddTopicPopup() error {
 	if h.layout.IsPopupActive() {
 		return nil
 	}
 	return h.layout.ShowAddTopicPopup()
 }
+
+func (h *keyBindingHandler) showDeleteTopicsPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowDeleteTopicsPopup()
+}
+
+func (h *keyBindingHandler) showAddPartitionsPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowAddPartitionsPopup()
+}
+
+func (h *keyBindingHandler) showTruncatePopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowTruncatePopup()
+}
+
+func (h *keyBindingHandler) showMessageQueryPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowMessageQueryPopup()
+}
+
+func (h *keyBindingHandler) showProducePopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowProducePopup()
+}
+
+func (h *keyBindingHandler) showEditTopicConfigPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowEditTopicConfigPopup()
+}
+
+func (h *keyBindingHandler) showResetOffsetsPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowResetOffsetsPopup()
+}
+
+func (h *keyBindingHandler) showDeleteConsumerGroupPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowDeleteConsumerGroupPopup()
+}
+
+func (h *keyBindingHandler) showDeleteGroupOffsetsPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowDeleteGroupOffsetsPopup()
+}
+
+func (h *keyBindingHandler) showRegisterSchemaPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowRegisterSchemaPopup()
+}
+
+func (h *keyBindingHandler) showSubjectCompatibilityPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowCompatibilityPopup(false)
+}
+
+func (h *keyBindingHandler) showGlobalCompatibilityPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowCompatibilityPopup(true)
+}
+
+func (h *keyBindingHandler) showDeleteSchemaPopup() error {
+	if h.layout.IsPopupActive() {
+		return nil
+	}
+	return h.layout.ShowDeleteSchemaPopup()
+}
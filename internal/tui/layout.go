@@ -2,21 +2,32 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/jroimartin/gocui"
 	"github.com/jurabek/lazykafka/internal/data"
 	"github.com/jurabek/lazykafka/internal/kafka"
 	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/schemaregistry"
 	"github.com/jurabek/lazykafka/internal/secrets"
+	"github.com/jurabek/lazykafka/internal/tui/types"
 	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
 	"github.com/jurabek/lazykafka/internal/tui/views"
 )
 
 const sidebarWidth = 40
 
+const adminTimeout = 15 * time.Second
+
+// connectTimeout bounds the connection test of the broker wizard
+const connectTimeout = 10 * time.Second
+
 const (
 	sidebarBrokers = iota
 	sidebarTopics
@@ -33,7 +44,6 @@ type Layout struct {
 	mainVM            *viewmodel.MainViewModel
 	popupManager      *PopupManager
 	brokerStorage     data.BrokerStorage
-	secretStore       secrets.SecretStore
 	brokerConfigs     []models.BrokerConfig
 	statusMessage     string
 	statusMu          sync.RWMutex
@@ -59,9 +69,11 @@ func NewLayout(ctx context.Context, g *gocui.Gui) *Layout {
 	topicDetailView := views.NewTopicDetailView(mainVM.TopicDetailVM())
 	cgDetailView := views.NewConsumerGroupDetailView(mainVM.ConsumerGroupDetailVM())
 	srDetailView := views.NewSchemaRegistryDetailView(mainVM.SchemaRegistryDetailVM())
+	clusterDetailView := views.NewClusterDetailView(mainVM.ClusterDetailVM())
 
 	sidebarViews := []views.View{brokersView, topicsView, cgView, srView}
 	detailViews := map[int]views.View{
+		sidebarBrokers:        clusterDetailView,
 		sidebarTopics:         topicDetailView,
 		sidebarConsumerGroups: cgDetailView,
 		sidebarSchemaRegistry: srDetailView,
@@ -77,8 +89,6 @@ func NewLayout(ctx context.Context, g *gocui.Gui) *Layout {
 
 	mainVM.BrokersVM().SetGui(g)
 
-	secretStore := secrets.NewKeyringStore()
-
 	layout := &Layout{
 		sidebarViews:      sidebarViews,
 		detailViews:       detailViews,
@@ -87,18 +97,25 @@ func NewLayout(ctx context.Context, g *gocui.Gui) *Layout {
 		gui:               g,
 		mainVM:            mainVM,
 		brokerStorage:     brokerStorage,
-		secretStore:       secretStore,
 		brokerConfigs:     configs,
 	}
 
 	mainVM.SetOnError(func(err error) {
 		layout.SetStatusMessage(err.Error())
 	})
+	mainVM.SetSecretStore(models.SecretKeyring, secrets.NewKeyringStore())
+	if path, err := secrets.DefaultSecretFilePath(); err == nil {
+		mainVM.SetSecretStore(models.SecretEncryptedFile, secrets.NewEncryptedFileStore(path))
+	}
+	mainVM.SetOnSecretRequired(layout.promptForSecret)
+	mainVM.BrokersVM().SetOnConfigEdited(layout.reloadBrokerConfigs)
 
 	layout.popupManager = NewPopupManager(g, layout, func(config models.BrokerConfig) {
 		layout.onBrokerAdded(config)
 	}, func(config models.TopicConfig) {
 		layout.onTopicAdded(config)
+	}, func(group string, previews []models.OffsetResetPreview) {
+		layout.onOffsetsReset(group, previews)
 	})
 
 	return layout
@@ -253,8 +270,27 @@ func (l *Layout) renderHelpView(v *gocui.View, maxX, maxY, helpHeight int) {
 	if statusMsg != "" {
 		fmt.Fprintf(v, " Error: %s\n", statusMsg)
 	} else {
-		fmt.Fprintln(v, " ←/→: switch panel | ↑/k: up | ↓/j: down | 1-4: jump panel | n: new | e: edit config | q: quit")
+		hints := " ←/→: switch panel | ↑/k: up | ↓/j: down | 1-4: jump panel"
+		if panelHints := l.panelHints(); panelHints != "" {
+			hints += " | " + panelHints
+		}
+		fmt.Fprintln(v, hints+" | q: quit")
+	}
+}
+
+// panelHints returns the key hints for the active sidebar panel
+func (l *Layout) panelHints() string {
+	switch l.activeViewIndex {
+	case sidebarBrokers:
+		return "n: new | E: edit | d: delete | e: edit brokers.json | [/]: switch tab | r: refresh"
+	case sidebarTopics:
+		return "n: new | space: mark | d: delete | a: add partitions | t: truncate | c: edit config | P: produce | [/]: switch tab | o: browse from | </>: page | f: follow | p: pause"
+	case sidebarConsumerGroups:
+		return "[/]: switch tab | r: reset offsets | d: delete group | x: delete topic offsets"
+	case sidebarSchemaRegistry:
+		return "n: register/check | c/C: subject/global compatibility | d: delete | r: refresh | [/]: switch tab | </>: version | b: mark diff base"
 	}
+	return ""
 }
 
 func (l *Layout) NextPanel(g *gocui.Gui) {
@@ -296,13 +332,250 @@ func (l *Layout) MainViewModel() *viewmodel.MainViewModel {
 }
 
 func (l *Layout) ShowAddBrokerPopup() error {
-	return l.popupManager.ShowAddBrokerPopup()
+	return l.popupManager.ShowAddBrokerPopup(l.testConnection)
 }
 
 func (l *Layout) ShowAddTopicPopup() error {
 	return l.popupManager.ShowAddTopicPopup()
 }
 
+func (l *Layout) ShowResetOffsetsPopup() error {
+	cgDetailVM := l.mainVM.ConsumerGroupDetailVM()
+	if err := cgDetailVM.CanResetOffsets(); err != nil {
+		if !errors.Is(err, types.ErrNoSelection) {
+			l.SetStatusMessage(err.Error())
+		}
+		return nil
+	}
+
+	cg := cgDetailVM.GetConsumerGroup()
+	defaultTopic := ""
+	if topics := cgDetailVM.GetOffsetTopics(); len(topics) > 0 {
+		defaultTopic = topics[0]
+	}
+
+	return l.popupManager.ShowResetOffsetsPopup(cg.Name, defaultTopic, func(spec models.OffsetResetSpec) ([]models.OffsetResetPreview, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+		defer cancel()
+		return cgDetailVM.PreviewOffsetReset(ctx, spec)
+	})
+}
+
+// ShowDeleteTopicsPopup deletes the marked topics, or the selected one when
+// none are marked
+func (l *Layout) ShowDeleteTopicsPopup() error {
+	topicsVM := l.mainVM.TopicsVM()
+	names := topicsVM.GetMarkedTopics()
+	if len(names) == 0 {
+		topic := topicsVM.GetSelectedTopic()
+		if topic == nil {
+			return nil
+		}
+		names = []string{topic.Name}
+	}
+
+	expected := names[0]
+	if len(names) > 1 {
+		expected = fmt.Sprintf("delete %d topics", len(names))
+	}
+	action := "Delete topic " + strings.Join(names, ", ")
+	return l.popupManager.ShowConfirmPopup(action, expected, nil, func(string) {
+		l.onTopicsDeleted(names)
+	})
+}
+
+func (l *Layout) ShowAddPartitionsPopup() error {
+	topic := l.mainVM.TopicsVM().GetSelectedTopic()
+	if topic == nil {
+		return nil
+	}
+
+	selected := *topic
+	checkKeyed := func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+		defer cancel()
+		return l.mainVM.TopicDetailVM().HasKeyedRecords(ctx, &selected)
+	}
+	return l.popupManager.ShowAddPartitionsPopup(selected.Name, selected.Partitions, checkKeyed, l.onPartitionsAdded)
+}
+
+// ShowTruncatePopup previews the records to delete and then asks for the
+// topic name before deleting them
+func (l *Layout) ShowTruncatePopup() error {
+	topicDetailVM := l.mainVM.TopicDetailVM()
+	topic := topicDetailVM.GetTopic()
+	if topic == nil {
+		return nil
+	}
+
+	preview := func(spec models.TruncateSpec) ([]models.TruncatePreview, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+		defer cancel()
+		return topicDetailVM.PreviewTruncate(ctx, spec)
+	}
+	return l.popupManager.ShowTruncatePopup(topic.Name, preview, func(name string, previews []models.TruncatePreview) {
+		var total int64
+		for _, p := range previews {
+			total += p.DeletedRecords()
+		}
+		action := fmt.Sprintf("Delete %d records of topic %s", total, name)
+		if err := l.popupManager.ShowConfirmPopup(action, name, nil, func(string) {
+			l.onRecordsDeleted(previews)
+		}); err != nil {
+			slog.Error("failed to show confirmation", slog.Any("error", err))
+		}
+	})
+}
+
+func (l *Layout) ShowRegisterSchemaPopup() error {
+	schemaRegistryVM := l.mainVM.SchemaRegistryVM()
+	if !schemaRegistryVM.HasSchemaRegistry() {
+		l.SetStatusMessage("no schema registry configured for this broker")
+		return nil
+	}
+
+	check := func(schema models.SchemaRegistry) (models.CompatibilityResult, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+		defer cancel()
+		return schemaRegistryVM.CheckCompatibility(ctx, schema)
+	}
+	latest := l.mainVM.SchemaRegistryDetailVM().GetSchema()
+	return l.popupManager.ShowRegisterSchemaPopup(latest, check, l.onSchemaRegistered)
+}
+
+// useGlobalLevel is the compatibility option making a subject fall back to
+// the global level
+const useGlobalLevel = "global default"
+
+// ShowCompatibilityPopup changes the compatibility level of the selected
+// subject, or the registry-wide level when global is set
+func (l *Layout) ShowCompatibilityPopup(global bool) error {
+	if !l.mainVM.SchemaRegistryVM().HasSchemaRegistry() {
+		l.SetStatusMessage("no schema registry configured for this broker")
+		return nil
+	}
+
+	levels := schemaregistry.CompatibilityLevels
+	subject, expected := "", "global"
+	action := "Set global compatibility to"
+	if !global {
+		schema := l.mainVM.SchemaRegistryDetailVM().GetSchema()
+		if schema == nil {
+			return nil
+		}
+		subject, expected = schema.Subject, schema.Subject
+		action = fmt.Sprintf("Set compatibility of %s to", subject)
+		levels = append(slices.Clone(levels), useGlobalLevel)
+	}
+
+	return l.popupManager.ShowConfirmPopup(action, expected, levels, func(level string) {
+		l.onCompatibilityChanged(subject, level)
+	})
+}
+
+// ShowDeleteSchemaPopup soft or hard deletes the selected subject or the
+// version viewed in the detail panel
+func (l *Layout) ShowDeleteSchemaPopup() error {
+	detailVM := l.mainVM.SchemaRegistryDetailVM()
+	schema := detailVM.GetSchema()
+	if schema == nil {
+		return nil
+	}
+
+	subject := schema.Subject
+	version := detailVM.GetSelectedVersion()
+	softSubject := "subject " + subject + " (soft delete)"
+	hardSubject := "subject " + subject + " permanently (hard delete)"
+	softVersion := fmt.Sprintf("version %d (soft delete)", version)
+	hardVersion := fmt.Sprintf("version %d permanently (hard delete)", version)
+	options := []string{softVersion, hardVersion, softSubject, hardSubject}
+
+	return l.popupManager.ShowConfirmPopup("Delete", subject, options, func(choice string) {
+		switch choice {
+		case softVersion:
+			l.onSchemaDeleted(subject, version, false)
+		case hardVersion:
+			l.onSchemaDeleted(subject, version, true)
+		case softSubject:
+			l.onSchemaDeleted(subject, 0, false)
+		case hardSubject:
+			l.onSchemaDeleted(subject, 0, true)
+		}
+	})
+}
+
+func (l *Layout) ShowMessageQueryPopup() error {
+	topicDetailVM := l.mainVM.TopicDetailVM()
+	topic := topicDetailVM.GetTopic()
+	if topic == nil {
+		return nil
+	}
+
+	return l.popupManager.ShowMessageQueryPopup(topic.Name, func(query models.MessageQuery) {
+		topicDetailVM.BrowseMessages(query)
+		topicDetailVM.SetActiveTab(viewmodel.TabMessages)
+	})
+}
+
+func (l *Layout) ShowProducePopup() error {
+	topic := l.mainVM.TopicDetailVM().GetTopic()
+	if topic == nil {
+		return nil
+	}
+
+	return l.popupManager.ShowProducePopup(topic.Name, l.onProduce)
+}
+
+func (l *Layout) ShowEditTopicConfigPopup() error {
+	topicDetailVM := l.mainVM.TopicDetailVM()
+	topic := topicDetailVM.GetTopic()
+	if topic == nil {
+		return nil
+	}
+
+	name := topic.Name
+	validate := func(alteration models.ConfigAlteration) error {
+		ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+		defer cancel()
+		return topicDetailVM.ValidateConfig(ctx, name, alteration)
+	}
+	return l.popupManager.ShowEditTopicConfigPopup(name, topicDetailVM.GetConfigs(), validate, func(alteration models.ConfigAlteration) {
+		l.onTopicConfigAltered(name, alteration)
+	})
+}
+
+func (l *Layout) ShowDeleteConsumerGroupPopup() error {
+	cg := l.mainVM.ConsumerGroupsVM().GetSelectedConsumerGroup()
+	if cg == nil {
+		return nil
+	}
+
+	group := cg.Name
+	return l.popupManager.ShowConfirmPopup("Delete consumer group "+group, group, nil, func(string) {
+		l.onConsumerGroupDeleted(group)
+	})
+}
+
+func (l *Layout) ShowDeleteGroupOffsetsPopup() error {
+	cgDetailVM := l.mainVM.ConsumerGroupDetailVM()
+	cg := cgDetailVM.GetConsumerGroup()
+	if cg == nil {
+		return nil
+	}
+
+	topics := cgDetailVM.GetOffsetTopics()
+	if len(topics) == 0 {
+		l.SetStatusMessage(fmt.Sprintf("consumer group %q has no committed offsets", cg.Name))
+		return nil
+	}
+
+	group := cg.Name
+	action := fmt.Sprintf("Delete committed offsets of %s for topic", group)
+	return l.popupManager.ShowConfirmPopup(action, group, topics, func(topic string) {
+		l.onGroupOffsetsDeleted(group, topic)
+	})
+}
+
 func (l *Layout) GetActiveViewIndex() int {
 	return l.activeViewIndex
 }
@@ -315,26 +588,149 @@ func (l *Layout) ClosePopup() {
 	l.popupManager.Close()
 }
 
+// promptForSecret asks for a secret the secret stores could not provide. It
+// may be called while the layout is being built, so the popup is shown on the
+// next GUI update.
+func (l *Layout) promptForSecret(title string, onSubmit func(secret string)) {
+	l.gui.Update(func(g *gocui.Gui) error {
+		if err := l.popupManager.ShowPasswordPopup(title, onSubmit); err != nil {
+			slog.Error("failed to show password prompt", slog.Any("error", err))
+		}
+		return nil
+	})
+}
+
 func (l *Layout) onBrokerAdded(config models.BrokerConfig) {
+	// secrets are stored by broker name, a second broker would overwrite them
+	for _, c := range l.brokerConfigs {
+		if c.Name == config.Name {
+			l.SetStatusMessage(fmt.Sprintf("broker %q already exists", config.Name))
+			return
+		}
+	}
+
+	l.mainVM.SaveCredentials(config)
+	config = withoutSecret(config)
+
 	l.mainVM.BrokersVM().AddBrokerConfig(config)
 	l.mainVM.AddBrokerConfig(config)
 	l.brokerConfigs = append(l.brokerConfigs, config)
+	l.saveBrokerConfigs()
+	l.renderBrokers()
+}
 
-	if config.AuthType == models.AuthSASL {
-		if l.secretStore != nil && config.Password != "" {
-			_ = l.secretStore.SaveCredentials(config.Name, config.Username, config.Password)
+func (l *Layout) testConnection(config models.BrokerConfig) (models.ClusterInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	return l.mainVM.TestConnection(ctx, config)
+}
+
+func (l *Layout) ShowEditBrokerPopup() error {
+	config, ok := l.selectedBrokerConfig()
+	if !ok {
+		return nil
+	}
+	test := func(updated models.BrokerConfig) (models.ClusterInfo, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+		defer cancel()
+		return l.mainVM.TestEditedConnection(ctx, config, updated)
+	}
+	return l.popupManager.ShowEditBrokerPopup(config, test, func(updated models.BrokerConfig) {
+		l.onBrokerUpdated(config, updated)
+	})
+}
+
+func (l *Layout) ShowDeleteBrokerPopup() error {
+	config, ok := l.selectedBrokerConfig()
+	if !ok {
+		return nil
+	}
+	return l.popupManager.ShowConfirmPopup("Delete broker "+config.Name, config.Name, nil, func(string) {
+		l.onBrokerDeleted(config)
+	})
+}
+
+func (l *Layout) selectedBrokerConfig() (models.BrokerConfig, bool) {
+	broker := l.mainVM.BrokersVM().GetSelectedBroker()
+	if broker == nil {
+		return models.BrokerConfig{}, false
+	}
+	for _, c := range l.brokerConfigs {
+		if c.Name == broker.Name {
+			return c, true
 		}
 	}
+	return models.BrokerConfig{}, false
+}
+
+func (l *Layout) onBrokerUpdated(old, updated models.BrokerConfig) {
+	// the wizard does not edit these, they are only set in brokers.json
+	updated.OAuthScope = old.OAuthScope
+	updated.SchemaRegistry = old.SchemaRegistry
+
+	configs := make([]models.BrokerConfig, 0, len(l.brokerConfigs))
+	for _, c := range l.brokerConfigs {
+		if c.Name == updated.Name && c.Name != old.Name {
+			l.SetStatusMessage(fmt.Sprintf("broker %q already exists", updated.Name))
+			return
+		}
+		if c.Name == old.Name {
+			c = withoutSecret(updated)
+		}
+		configs = append(configs, c)
+	}
+
+	l.mainVM.UpdateCredentials(old, updated)
+	l.setBrokerConfigs(configs)
+	l.saveBrokerConfigs()
+}
+
+func (l *Layout) onBrokerDeleted(config models.BrokerConfig) {
+	configs := make([]models.BrokerConfig, 0, len(l.brokerConfigs))
+	for _, c := range l.brokerConfigs {
+		if c.Name != config.Name {
+			configs = append(configs, c)
+		}
+	}
+
+	l.mainVM.DeleteCredentials(config)
+	l.setBrokerConfigs(configs)
+	l.saveBrokerConfigs()
+}
+
+// reloadBrokerConfigs picks up changes made to brokers.json outside the app.
+// Stored secrets of brokers removed from the file are kept, since a rename
+// looks the same as a removal.
+func (l *Layout) reloadBrokerConfigs() {
+	if l.brokerStorage == nil {
+		return
+	}
+	configs, err := l.brokerStorage.Load()
+	if err != nil {
+		slog.Error("reloading brokers failed", slog.Any("error", err))
+		l.SetStatusMessage("reloading brokers.json failed: " + err.Error())
+		return
+	}
+	l.setBrokerConfigs(configs)
+}
 
-	if l.brokerStorage != nil {
-		configToSave := config
-		configToSave.Password = ""
+func (l *Layout) setBrokerConfigs(configs []models.BrokerConfig) {
+	l.brokerConfigs = configs
+	l.mainVM.SetBrokerConfigs(configs)
+	l.renderBrokers()
+}
 
-		configs, _ := l.brokerStorage.Load()
-		configs = append(configs, configToSave)
-		_ = l.brokerStorage.Save(configs)
+func (l *Layout) saveBrokerConfigs() {
+	if l.brokerStorage == nil {
+		return
+	}
+	if err := l.brokerStorage.Save(l.brokerConfigs); err != nil {
+		slog.Error("saving brokers failed", slog.Any("error", err))
+		l.SetStatusMessage("saving brokers.json failed: " + err.Error())
 	}
+}
 
+func (l *Layout) renderBrokers() {
 	l.gui.Update(func(g *gocui.Gui) error {
 		if view, err := g.View(panelBrokers); err == nil {
 			brokersView := l.sidebarViews[sidebarBrokers]
@@ -344,6 +740,15 @@ func (l *Layout) onBrokerAdded(config models.BrokerConfig) {
 	})
 }
 
+// withoutSecret drops the password before a config is kept in memory or in
+// brokers.json. References are not secret and stay.
+func withoutSecret(config models.BrokerConfig) models.BrokerConfig {
+	if !secrets.IsReference(config.Password) {
+		config.Password = ""
+	}
+	return config
+}
+
 func (l *Layout) onTopicAdded(config models.TopicConfig) {
 	ctx := context.Background()
 	if err := l.mainVM.CreateTopic(ctx, config); err != nil {
@@ -362,3 +767,172 @@ func (l *Layout) onTopicAdded(config models.TopicConfig) {
 		return nil
 	})
 }
+
+// onProduce sends records in the background, brokers may take up to
+// adminTimeout to acknowledge them
+func (l *Layout) onProduce(records []models.ProduceRecord) {
+	l.SetStatusMessage(fmt.Sprintf("producing %d records...", len(records)))
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+		defer cancel()
+
+		results, err := l.mainVM.ProduceMessages(ctx, records)
+		if err != nil {
+			slog.Error("producing messages failed", slog.Int("produced", len(results)), slog.Any("error", err))
+			l.SetStatusMessage(fmt.Sprintf("produced %d of %d records: %s", len(results), len(records), err))
+		} else {
+			l.SetStatusMessage(describeProduced(results))
+		}
+
+		l.mainVM.TopicDetailVM().Refresh()
+	}()
+}
+
+// describeProduced names the partition and offsets records were written to,
+// as a range per partition
+func describeProduced(results []models.ProduceResult) string {
+	switch len(results) {
+	case 0:
+		return "no records produced"
+	case 1:
+		return fmt.Sprintf("produced 1 record to partition %d at offset %d", results[0].Partition, results[0].Offset)
+	}
+
+	type offsetRange struct{ first, last int64 }
+	ranges := make(map[int]*offsetRange)
+	var partitions []int
+	for _, r := range results {
+		if rng, ok := ranges[r.Partition]; ok {
+			rng.first = min(rng.first, r.Offset)
+			rng.last = max(rng.last, r.Offset)
+			continue
+		}
+		ranges[r.Partition] = &offsetRange{first: r.Offset, last: r.Offset}
+		partitions = append(partitions, r.Partition)
+	}
+	slices.Sort(partitions)
+
+	parts := make([]string, len(partitions))
+	for i, p := range partitions {
+		rng := ranges[p]
+		if rng.first == rng.last {
+			parts[i] = fmt.Sprintf("partition %d offset %d", p, rng.first)
+		} else {
+			parts[i] = fmt.Sprintf("partition %d offsets %d-%d", p, rng.first, rng.last)
+		}
+	}
+	return fmt.Sprintf("produced %d records to %s", len(results), strings.Join(parts, ", "))
+}
+
+func (l *Layout) onTopicConfigAltered(topic string, alteration models.ConfigAlteration) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	topicDetailVM := l.mainVM.TopicDetailVM()
+	if err := topicDetailVM.AlterConfig(ctx, topic, alteration); err != nil {
+		slog.Error("altering topic config failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+		return
+	}
+	topicDetailVM.SetActiveTab(viewmodel.TabConfiguration)
+}
+
+func (l *Layout) onOffsetsReset(group string, previews []models.OffsetResetPreview) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if err := l.mainVM.ConsumerGroupDetailVM().ResetOffsets(ctx, group, previews); err != nil {
+		slog.Error("resetting offsets failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+		return
+	}
+
+	l.mainVM.ConsumerGroupsVM().Reload()
+}
+
+func (l *Layout) onSchemaRegistered(schema models.SchemaRegistry) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	id, err := l.mainVM.SchemaRegistryVM().RegisterSchema(ctx, schema)
+	if err != nil {
+		slog.Error("registering schema failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+		return
+	}
+	slog.Info("schema registered", slog.String("subject", schema.Subject), slog.Int("id", id))
+}
+
+func (l *Layout) onCompatibilityChanged(subject, level string) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if level == useGlobalLevel {
+		level = ""
+	}
+	if err := l.mainVM.SchemaRegistryDetailVM().SetCompatibility(ctx, subject, level); err != nil {
+		slog.Error("changing compatibility failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+	}
+}
+
+func (l *Layout) onSchemaDeleted(subject string, version int, permanent bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if err := l.mainVM.SchemaRegistryVM().DeleteSubject(ctx, subject, version, permanent); err != nil {
+		slog.Error("deleting schema failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+	}
+}
+
+func (l *Layout) onPartitionsAdded(topic string, total int) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if err := l.mainVM.TopicsVM().UpdatePartitions(ctx, topic, total); err != nil {
+		slog.Error("adding partitions failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+	}
+}
+
+func (l *Layout) onRecordsDeleted(previews []models.TruncatePreview) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if err := l.mainVM.TopicDetailVM().DeleteRecords(ctx, previews); err != nil {
+		slog.Error("deleting records failed", slog.Any("error", err))
+		l.SetStatusMessage(strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+}
+
+func (l *Layout) onTopicsDeleted(names []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if err := l.mainVM.TopicsVM().DeleteTopics(ctx, names); err != nil {
+		slog.Error("deleting topics failed", slog.Any("error", err))
+		l.SetStatusMessage(strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+}
+
+func (l *Layout) onConsumerGroupDeleted(group string) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if err := l.mainVM.ConsumerGroupsVM().DeleteConsumerGroup(ctx, group); err != nil {
+		slog.Error("deleting consumer group failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+	}
+}
+
+func (l *Layout) onGroupOffsetsDeleted(group, topic string) {
+	ctx, cancel := context.WithTimeout(context.Background(), adminTimeout)
+	defer cancel()
+
+	if err := l.mainVM.ConsumerGroupDetailVM().DeleteOffsets(ctx, group, topic); err != nil {
+		slog.Error("deleting consumer group offsets failed", slog.Any("error", err))
+		l.SetStatusMessage(err.Error())
+	}
+}
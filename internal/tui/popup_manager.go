@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"log/slog"
+
 	"github.com/jroimartin/gocui"
 	"github.com/jurabek/lazykafka/internal/models"
 	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
@@ -8,25 +10,58 @@ import (
 )
 
 type PopupManager struct {
-	gui             *gocui.Gui
-	layout          *Layout
-	addBrokerView   *views.AddBrokerView
-	addBrokerVM     *viewmodel.AddBrokerViewModel
-	addTopicView    *views.AddTopicView
-	addTopicVM      *viewmodel.AddTopicViewModel
-	isPopupActive   bool
-	activePopupView string
-	previousView    string
-	onBrokerAdded   func(config models.BrokerConfig)
-	onTopicAdded    func(config models.TopicConfig)
-}
-
-func NewPopupManager(g *gocui.Gui, layout *Layout, onBrokerAdded func(models.BrokerConfig), onTopicAdded func(models.TopicConfig)) *PopupManager {
+	gui               *gocui.Gui
+	layout            *Layout
+	addBrokerView     *views.AddBrokerView
+	addBrokerVM       *viewmodel.AddBrokerViewModel
+	addTopicView      *views.AddTopicView
+	addTopicVM        *viewmodel.AddTopicViewModel
+	resetOffsetsView  *views.ResetOffsetsView
+	resetOffsetsVM    *viewmodel.ResetOffsetsViewModel
+	confirmView       *views.ConfirmView
+	confirmVM         *viewmodel.ConfirmViewModel
+	messageQueryView  *views.MessageQueryView
+	messageQueryVM    *viewmodel.MessageQueryViewModel
+	produceView       *views.ProduceView
+	produceVM         *viewmodel.ProduceViewModel
+	editConfigView    *views.EditTopicConfigView
+	editConfigVM      *viewmodel.EditTopicConfigViewModel
+	addPartitionsView *views.AddPartitionsView
+	addPartitionsVM   *viewmodel.AddPartitionsViewModel
+	truncateView      *views.TruncateView
+	truncateVM        *viewmodel.TruncateViewModel
+	registerView      *views.RegisterSchemaView
+	registerVM        *viewmodel.RegisterSchemaViewModel
+	passwordView      *views.PasswordView
+	passwordVM        *viewmodel.PasswordViewModel
+	pendingPasswords  []passwordPrompt
+	isPopupActive     bool
+	activePopupView   string
+	previousView      string
+	onBrokerAdded     func(config models.BrokerConfig)
+	onTopicAdded      func(config models.TopicConfig)
+	onOffsetsReset    func(group string, previews []models.OffsetResetPreview)
+}
+
+// passwordPrompt is a secret prompt waiting for the open popup to close
+type passwordPrompt struct {
+	title    string
+	onSubmit func(password string)
+}
+
+func NewPopupManager(
+	g *gocui.Gui,
+	layout *Layout,
+	onBrokerAdded func(models.BrokerConfig),
+	onTopicAdded func(models.TopicConfig),
+	onOffsetsReset func(string, []models.OffsetResetPreview),
+) *PopupManager {
 	return &PopupManager{
-		gui:           g,
-		layout:        layout,
-		onBrokerAdded: onBrokerAdded,
-		onTopicAdded:  onTopicAdded,
+		gui:            g,
+		layout:         layout,
+		onBrokerAdded:  onBrokerAdded,
+		onTopicAdded:   onTopicAdded,
+		onOffsetsReset: onOffsetsReset,
 	}
 }
 
@@ -34,7 +69,7 @@ func (pm *PopupManager) IsActive() bool {
 	return pm.isPopupActive
 }
 
-func (pm *PopupManager) ShowAddBrokerPopup() error {
+func (pm *PopupManager) ShowAddBrokerPopup(test viewmodel.ConnectionTestFunc) error {
 	if pm.isPopupActive {
 		return nil
 	}
@@ -45,6 +80,7 @@ func (pm *PopupManager) ShowAddBrokerPopup() error {
 	}
 
 	pm.addBrokerVM = viewmodel.NewAddBrokerViewModel(
+		test,
 		func(config models.BrokerConfig) {
 			if pm.onBrokerAdded != nil {
 				pm.onBrokerAdded(config)
@@ -69,6 +105,48 @@ func (pm *PopupManager) ShowAddBrokerPopup() error {
 	return nil
 }
 
+// ShowEditBrokerPopup opens the broker wizard prefilled with config
+func (pm *PopupManager) ShowEditBrokerPopup(
+	config models.BrokerConfig,
+	test viewmodel.ConnectionTestFunc,
+	onSubmit func(updated models.BrokerConfig),
+) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.addBrokerVM = viewmodel.NewEditBrokerViewModel(
+		config,
+		test,
+		func(updated models.BrokerConfig) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(updated)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.addBrokerView = views.NewAddBrokerView(pm.addBrokerVM, pm.Close)
+	pm.isPopupActive = true
+	pm.activePopupView = "wizard_input"
+
+	if err := pm.addBrokerView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
 func (pm *PopupManager) ShowAddTopicPopup() error {
 	if pm.isPopupActive {
 		return nil
@@ -104,6 +182,364 @@ func (pm *PopupManager) ShowAddTopicPopup() error {
 	return nil
 }
 
+func (pm *PopupManager) ShowResetOffsetsPopup(group, defaultTopic string, preview viewmodel.OffsetResetPreviewFunc) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.resetOffsetsVM = viewmodel.NewResetOffsetsViewModel(
+		group,
+		defaultTopic,
+		preview,
+		func(group string, previews []models.OffsetResetPreview) {
+			if pm.onOffsetsReset != nil {
+				pm.onOffsetsReset(group, previews)
+			}
+			pm.Close()
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.resetOffsetsView = views.NewResetOffsetsView(pm.resetOffsetsVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "reset_offsets_input"
+
+	if err := pm.resetOffsetsView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+// ShowConfirmPopup asks the user to type expected before running onConfirm.
+// When options are given the user first picks one, which is passed to onConfirm.
+func (pm *PopupManager) ShowConfirmPopup(action, expected string, options []string, onConfirm func(choice string)) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.confirmVM = viewmodel.NewConfirmViewModel(
+		action,
+		expected,
+		options,
+		func(choice string) {
+			pm.Close()
+			if onConfirm != nil {
+				onConfirm(choice)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.confirmView = views.NewConfirmView(pm.confirmVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "confirm_input"
+
+	if err := pm.confirmView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+func (pm *PopupManager) ShowMessageQueryPopup(topic string, onSubmit func(query models.MessageQuery)) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.messageQueryVM = viewmodel.NewMessageQueryViewModel(
+		topic,
+		func(query models.MessageQuery) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(query)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.messageQueryView = views.NewMessageQueryView(pm.messageQueryVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "message_query_input"
+
+	if err := pm.messageQueryView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+func (pm *PopupManager) ShowProducePopup(topic string, onSubmit func(records []models.ProduceRecord)) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.produceVM = viewmodel.NewProduceViewModel(
+		topic,
+		func(records []models.ProduceRecord) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(records)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.produceView = views.NewProduceView(pm.produceVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "produce_input"
+
+	if err := pm.produceView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+func (pm *PopupManager) ShowEditTopicConfigPopup(
+	topic string,
+	configs []models.ConfigEntry,
+	validate viewmodel.ConfigValidateFunc,
+	onSubmit func(alteration models.ConfigAlteration),
+) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.editConfigVM = viewmodel.NewEditTopicConfigViewModel(
+		topic,
+		configs,
+		validate,
+		func(alteration models.ConfigAlteration) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(alteration)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.editConfigView = views.NewEditTopicConfigView(pm.editConfigVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "edit_topic_config_input"
+
+	if err := pm.editConfigView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+func (pm *PopupManager) ShowAddPartitionsPopup(
+	topic string,
+	current int,
+	checkKeyed viewmodel.KeyedCheckFunc,
+	onSubmit func(topic string, total int),
+) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.addPartitionsVM = viewmodel.NewAddPartitionsViewModel(
+		topic,
+		current,
+		checkKeyed,
+		func(topic string, total int) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(topic, total)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.addPartitionsView = views.NewAddPartitionsView(pm.addPartitionsVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "add_partitions_input"
+
+	if err := pm.addPartitionsView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+func (pm *PopupManager) ShowTruncatePopup(
+	topic string,
+	preview viewmodel.TruncatePreviewFunc,
+	onSubmit func(topic string, previews []models.TruncatePreview),
+) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.truncateVM = viewmodel.NewTruncateViewModel(
+		topic,
+		preview,
+		func(topic string, previews []models.TruncatePreview) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(topic, previews)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.truncateView = views.NewTruncateView(pm.truncateVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "truncate_input"
+
+	if err := pm.truncateView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+// ShowRegisterSchemaPopup registers a new schema version or only checks it
+func (pm *PopupManager) ShowRegisterSchemaPopup(
+	latest *models.SchemaRegistry,
+	check viewmodel.CompatibilityCheckFunc,
+	onSubmit func(schema models.SchemaRegistry),
+) error {
+	if pm.isPopupActive {
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.registerVM = viewmodel.NewRegisterSchemaViewModel(
+		latest,
+		check,
+		func(schema models.SchemaRegistry) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(schema)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.registerView = views.NewRegisterSchemaView(pm.registerVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "register_schema_input"
+
+	if err := pm.registerView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
+// ShowPasswordPopup asks for a secret with masked input. While another popup
+// is open the prompt waits for it to close.
+func (pm *PopupManager) ShowPasswordPopup(title string, onSubmit func(password string)) error {
+	if pm.isPopupActive {
+		pm.queuePasswordPopup(title, onSubmit)
+		return nil
+	}
+
+	currentView := pm.gui.CurrentView()
+	if currentView != nil {
+		pm.previousView = currentView.Name()
+	}
+
+	pm.passwordVM = viewmodel.NewPasswordViewModel(
+		title,
+		func(password string) {
+			pm.Close()
+			if onSubmit != nil {
+				onSubmit(password)
+			}
+		},
+		func() {
+			pm.Close()
+		},
+	)
+
+	pm.passwordView = views.NewPasswordView(pm.passwordVM)
+	pm.isPopupActive = true
+	pm.activePopupView = "password_input"
+
+	if err := pm.passwordView.Initialize(pm.gui); err != nil {
+		pm.isPopupActive = false
+		pm.activePopupView = ""
+		return err
+	}
+
+	return nil
+}
+
 func (pm *PopupManager) Close() {
 	if !pm.isPopupActive {
 		return
@@ -119,14 +555,99 @@ func (pm *PopupManager) Close() {
 		pm.addTopicView = nil
 	}
 
+	if pm.resetOffsetsView != nil {
+		_ = pm.resetOffsetsView.Destroy(pm.gui)
+		pm.resetOffsetsView = nil
+	}
+
+	if pm.confirmView != nil {
+		_ = pm.confirmView.Destroy(pm.gui)
+		pm.confirmView = nil
+	}
+
+	if pm.messageQueryView != nil {
+		_ = pm.messageQueryView.Destroy(pm.gui)
+		pm.messageQueryView = nil
+	}
+
+	if pm.produceView != nil {
+		_ = pm.produceView.Destroy(pm.gui)
+		pm.produceView = nil
+	}
+
+	if pm.editConfigView != nil {
+		_ = pm.editConfigView.Destroy(pm.gui)
+		pm.editConfigView = nil
+	}
+
+	if pm.addPartitionsView != nil {
+		_ = pm.addPartitionsView.Destroy(pm.gui)
+		pm.addPartitionsView = nil
+	}
+
+	if pm.truncateView != nil {
+		_ = pm.truncateView.Destroy(pm.gui)
+		pm.truncateView = nil
+	}
+
+	if pm.passwordView != nil {
+		_ = pm.passwordView.Destroy(pm.gui)
+		pm.passwordView = nil
+	}
+
+	if pm.registerView != nil {
+		_ = pm.registerView.Destroy(pm.gui)
+		pm.registerView = nil
+	}
+
 	pm.addBrokerVM = nil
 	pm.addTopicVM = nil
+	pm.resetOffsetsVM = nil
+	pm.confirmVM = nil
+	pm.messageQueryVM = nil
+	pm.produceVM = nil
+	pm.editConfigVM = nil
+	pm.addPartitionsVM = nil
+	pm.truncateVM = nil
+	pm.passwordVM = nil
+	pm.registerVM = nil
 	pm.isPopupActive = false
 	pm.activePopupView = ""
 
 	if pm.previousView != "" {
 		_, _ = pm.gui.SetCurrentView(pm.previousView)
 	}
+
+	if len(pm.pendingPasswords) > 0 {
+		// shown after the callback of the closed popup has run
+		pm.gui.Update(func(g *gocui.Gui) error {
+			pm.showPendingPasswordPopup()
+			return nil
+		})
+	}
+}
+
+// queuePasswordPopup keeps a secret prompt until the open popup is closed.
+// A broker selected again while waiting replaces its earlier prompt.
+func (pm *PopupManager) queuePasswordPopup(title string, onSubmit func(password string)) {
+	for i := range pm.pendingPasswords {
+		if pm.pendingPasswords[i].title == title {
+			pm.pendingPasswords[i].onSubmit = onSubmit
+			return
+		}
+	}
+	pm.pendingPasswords = append(pm.pendingPasswords, passwordPrompt{title: title, onSubmit: onSubmit})
+}
+
+func (pm *PopupManager) showPendingPasswordPopup() {
+	if pm.isPopupActive || len(pm.pendingPasswords) == 0 {
+		return
+	}
+	next := pm.pendingPasswords[0]
+	pm.pendingPasswords = pm.pendingPasswords[1:]
+	if err := pm.ShowPasswordPopup(next.title, next.onSubmit); err != nil {
+		slog.Error("failed to show password prompt", slog.Any("error", err))
+	}
 }
 
 func (pm *PopupManager) BringToTop() {
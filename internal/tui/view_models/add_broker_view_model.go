@@ -2,11 +2,16 @@ package viewmodel
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/secrets"
 	"github.com/jurabek/lazykafka/internal/tui/types"
 )
 
@@ -19,10 +24,22 @@ const (
 	StepSASLMechanism    = 3
 	StepUsername         = 4
 	StepPassword         = 5
+	StepOAuthTokenURL    = 6
+	StepTLS              = 7
+	StepTLSCAFile        = 8
+	StepTLSCertFile      = 9
+	StepTLSKeyFile       = 10
+	StepTLSServerName    = 11
+	StepTLSVerify        = 12
+	StepSecretBackend    = 13
+	StepTestConnection   = 14
 )
 
 var ErrValidation = errors.New("validation error")
 
+// ConnectionTestFunc connects with config and describes the cluster
+type ConnectionTestFunc func(config models.BrokerConfig) (models.ClusterInfo, error)
+
 type AddBrokerViewModel struct {
 	mu               sync.RWMutex
 	name             string
@@ -31,14 +48,28 @@ type AddBrokerViewModel struct {
 	saslMechanism    models.SASLMechanism
 	username         string
 	password         string
+	oauthTokenURL    string
+	tlsEnabled       bool
+	tlsCAFile        string
+	tlsCertFile      string
+	tlsKeyFile       string
+	tlsServerName    string
+	tlsInsecure      bool
+	secretBackend    models.SecretBackend
+	editing          bool
+	test             ConnectionTestFunc
+	testing          bool
+	testInfo         *models.ClusterInfo
+	testErr          error
 	currentStep      int
 	onChange         types.OnChangeFunc
 	onSubmit         func(config models.BrokerConfig)
 	onCancel         func()
 }
 
-func NewAddBrokerViewModel(onSubmit func(models.BrokerConfig), onCancel func()) *AddBrokerViewModel {
+func NewAddBrokerViewModel(test ConnectionTestFunc, onSubmit func(models.BrokerConfig), onCancel func()) *AddBrokerViewModel {
 	return &AddBrokerViewModel{
+		test:        test,
 		currentStep: StepName,
 		authType:    models.AuthNone,
 		onSubmit:    onSubmit,
@@ -46,6 +77,69 @@ func NewAddBrokerViewModel(onSubmit func(models.BrokerConfig), onCancel func())
 	}
 }
 
+// NewEditBrokerViewModel prefills the wizard with config. The secret is not
+// shown, leaving the password empty keeps it.
+func NewEditBrokerViewModel(
+	config models.BrokerConfig,
+	test ConnectionTestFunc,
+	onSubmit func(models.BrokerConfig),
+	onCancel func(),
+) *AddBrokerViewModel {
+	vm := NewAddBrokerViewModel(test, onSubmit, onCancel)
+	vm.editing = true
+	vm.name = config.Name
+	vm.bootstrapServers = config.BootstrapServers
+	vm.authType = config.AuthType
+	vm.saslMechanism = config.SASLMechanism
+	vm.username = config.Username
+	vm.oauthTokenURL = config.OAuthTokenURL
+	vm.secretBackend = config.SecretBackend
+	if secrets.IsReference(config.Password) {
+		vm.password = config.Password
+	}
+	if config.TLS != nil {
+		vm.tlsEnabled = config.TLS.Enabled
+		vm.tlsCAFile = config.TLS.CAFile
+		vm.tlsCertFile = config.TLS.CertFile
+		vm.tlsKeyFile = config.TLS.KeyFile
+		vm.tlsServerName = config.TLS.ServerName
+		vm.tlsInsecure = config.TLS.InsecureSkipVerify
+	}
+	return vm
+}
+
+func (vm *AddBrokerViewModel) IsEditing() bool {
+	return vm.editing
+}
+
+// GetStepValue returns the current value of a text step, used to prefill the
+// input when editing
+func (vm *AddBrokerViewModel) GetStepValue() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepName:
+		return vm.name
+	case StepBootstrapServers:
+		return vm.bootstrapServers
+	case StepUsername:
+		return vm.username
+	case StepPassword:
+		return vm.password
+	case StepOAuthTokenURL:
+		return vm.oauthTokenURL
+	case StepTLSCAFile:
+		return vm.tlsCAFile
+	case StepTLSCertFile:
+		return vm.tlsCertFile
+	case StepTLSKeyFile:
+		return vm.tlsKeyFile
+	case StepTLSServerName:
+		return vm.tlsServerName
+	}
+	return ""
+}
+
 func (vm *AddBrokerViewModel) SetOnChange(fn types.OnChangeFunc) {
 	vm.onChange = fn
 }
@@ -70,14 +164,52 @@ func (vm *AddBrokerViewModel) GetStepTitle() string {
 		return "Broker name:"
 	case StepBootstrapServers:
 		return "Bootstrap servers:"
+	case StepTLS:
+		return "TLS (↑↓ to select, Enter to confirm):"
+	case StepTLSCAFile:
+		return "CA bundle path (leave empty for system roots):"
+	case StepTLSCertFile:
+		return "Client certificate path for mTLS (leave empty to skip):"
+	case StepTLSKeyFile:
+		return "Client key path:"
+	case StepTLSServerName:
+		return "Server name override (leave empty to use the broker host):"
+	case StepTLSVerify:
+		return "Server certificate verification (↑↓ to select, Enter to confirm):"
+	case StepSecretBackend:
+		return "Store the secret in (↑↓ to select, Enter to confirm):"
+	case StepTestConnection:
+		switch {
+		case vm.testInfo != nil:
+			return "Connected (Enter to save, Esc to cancel):"
+		case vm.testErr != nil && !vm.testing:
+			return "Connection failed (Enter to retry, s to save anyway, Esc to cancel):"
+		default:
+			return "Testing connection..."
+		}
 	case StepAuthType:
 		return "Auth type (↑↓ to select, Enter to confirm):"
 	case StepSASLMechanism:
 		return "SASL mechanism (↑↓ to select, Enter to confirm):"
+	case StepOAuthTokenURL:
+		return "Token endpoint URL (leave empty to use a static token):"
 	case StepUsername:
+		if vm.saslMechanism == models.SASLOAuthBearer {
+			return "Client ID:"
+		}
 		return "Username:"
 	case StepPassword:
-		return "Password:"
+		title := "Password (or env:VAR / cmd:command)"
+		if vm.saslMechanism == models.SASLOAuthBearer {
+			title = "Client secret"
+			if vm.oauthTokenURL == "" {
+				title = "Token"
+			}
+		}
+		if vm.editing {
+			title += ", leave empty to keep the current one"
+		}
+		return title + ":"
 	}
 	return ""
 }
@@ -90,20 +222,67 @@ func (vm *AddBrokerViewModel) NextStep() bool {
 	case StepName:
 		vm.currentStep = StepBootstrapServers
 	case StepBootstrapServers:
+		vm.currentStep = StepTLS
+	case StepTLS:
+		if vm.tlsEnabled {
+			vm.currentStep = StepTLSCAFile
+		} else {
+			vm.currentStep = StepAuthType
+		}
+	case StepTLSCAFile:
+		vm.currentStep = StepTLSCertFile
+	case StepTLSCertFile:
+		if vm.tlsCertFile != "" {
+			vm.currentStep = StepTLSKeyFile
+		} else {
+			vm.currentStep = StepTLSServerName
+		}
+	case StepTLSKeyFile:
+		vm.currentStep = StepTLSServerName
+	case StepTLSServerName:
+		vm.currentStep = StepTLSVerify
+	case StepTLSVerify:
 		vm.currentStep = StepAuthType
 	case StepAuthType:
 		if vm.authType == models.AuthSASL {
 			vm.currentStep = StepSASLMechanism
 		} else {
-			return true // done, submit
+			return vm.finishLocked()
 		}
 	case StepSASLMechanism:
-		vm.currentStep = StepUsername
+		if vm.saslMechanism == models.SASLOAuthBearer {
+			vm.currentStep = StepOAuthTokenURL
+		} else {
+			vm.currentStep = StepUsername
+		}
+	case StepOAuthTokenURL:
+		if vm.oauthTokenURL == "" {
+			vm.currentStep = StepPassword
+		} else {
+			vm.currentStep = StepUsername
+		}
 	case StepUsername:
 		vm.currentStep = StepPassword
 	case StepPassword:
+		if secrets.IsReference(vm.password) {
+			return vm.finishLocked()
+		}
+		vm.currentStep = StepSecretBackend
+	case StepSecretBackend:
+		return vm.finishLocked()
+	case StepTestConnection:
+		return true // done, submit
+	}
+	return false
+}
+
+// finishLocked moves to the connection test, or reports that the wizard is
+// done when there is nothing to test with
+func (vm *AddBrokerViewModel) finishLocked() bool {
+	if vm.test == nil {
 		return true // done, submit
 	}
+	vm.currentStep = StepTestConnection
 	return false
 }
 
@@ -114,15 +293,153 @@ func (vm *AddBrokerViewModel) PrevStep() {
 	switch vm.currentStep {
 	case StepBootstrapServers:
 		vm.currentStep = StepName
-	case StepAuthType:
+	case StepTLS:
 		vm.currentStep = StepBootstrapServers
+	case StepTLSCAFile:
+		vm.currentStep = StepTLS
+	case StepTLSCertFile:
+		vm.currentStep = StepTLSCAFile
+	case StepTLSKeyFile:
+		vm.currentStep = StepTLSCertFile
+	case StepTLSServerName:
+		if vm.tlsCertFile != "" {
+			vm.currentStep = StepTLSKeyFile
+		} else {
+			vm.currentStep = StepTLSCertFile
+		}
+	case StepTLSVerify:
+		vm.currentStep = StepTLSServerName
+	case StepSecretBackend:
+		vm.currentStep = StepPassword
+	case StepTestConnection:
+		switch {
+		case vm.authType != models.AuthSASL:
+			vm.currentStep = StepAuthType
+		case secrets.IsReference(vm.password):
+			vm.currentStep = StepPassword
+		default:
+			vm.currentStep = StepSecretBackend
+		}
+	case StepAuthType:
+		if vm.tlsEnabled {
+			vm.currentStep = StepTLSVerify
+		} else {
+			vm.currentStep = StepTLS
+		}
 	case StepSASLMechanism:
 		vm.currentStep = StepAuthType
-	case StepUsername:
+	case StepOAuthTokenURL:
 		vm.currentStep = StepSASLMechanism
+	case StepUsername:
+		if vm.saslMechanism == models.SASLOAuthBearer {
+			vm.currentStep = StepOAuthTokenURL
+		} else {
+			vm.currentStep = StepSASLMechanism
+		}
 	case StepPassword:
-		vm.currentStep = StepUsername
+		if vm.saslMechanism == models.SASLOAuthBearer && vm.oauthTokenURL == "" {
+			vm.currentStep = StepOAuthTokenURL
+		} else {
+			vm.currentStep = StepUsername
+		}
+	}
+}
+
+// IsSelectionStep reports whether the current step picks from a list instead
+// of taking text input
+func (vm *AddBrokerViewModel) IsSelectionStep() bool {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepAuthType, StepSASLMechanism, StepTLS, StepTLSVerify, StepSecretBackend, StepTestConnection:
+		return true
+	}
+	return false
+}
+
+func (vm *AddBrokerViewModel) GetTLSOptions() []string {
+	return []string{"Disabled", "Enabled"}
+}
+
+func (vm *AddBrokerViewModel) GetSelectedTLSIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.tlsEnabled {
+		return 1
+	}
+	return 0
+}
+
+func (vm *AddBrokerViewModel) ToggleTLS() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.tlsEnabled = !vm.tlsEnabled
+	vm.notifyChange("tlsEnabled")
+}
+
+func (vm *AddBrokerViewModel) GetTLSVerifyOptions() []string {
+	return []string{"Verify", "Skip verification (insecure)"}
+}
+
+func (vm *AddBrokerViewModel) GetSelectedTLSVerifyIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.tlsInsecure {
+		return 1
+	}
+	return 0
+}
+
+func (vm *AddBrokerViewModel) ToggleTLSVerify() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.tlsInsecure = !vm.tlsInsecure
+	vm.notifyChange("tlsInsecure")
+}
+
+func (vm *AddBrokerViewModel) GetSecretBackendOptions() []string {
+	return []string{models.SecretKeyring.String(), models.SecretEncryptedFile.String()}
+}
+
+func (vm *AddBrokerViewModel) GetSelectedSecretBackendIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.secretBackend)
+}
+
+func (vm *AddBrokerViewModel) ToggleSecretBackend() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.secretBackend == models.SecretKeyring {
+		vm.secretBackend = models.SecretEncryptedFile
+	} else {
+		vm.secretBackend = models.SecretKeyring
 	}
+	vm.notifyChange("secretBackend")
+}
+
+func (vm *AddBrokerViewModel) SetTLSCAFile(path string) {
+	vm.mu.Lock()
+	vm.tlsCAFile = strings.TrimSpace(path)
+	vm.mu.Unlock()
+}
+
+func (vm *AddBrokerViewModel) SetTLSCertFile(path string) {
+	vm.mu.Lock()
+	vm.tlsCertFile = strings.TrimSpace(path)
+	vm.mu.Unlock()
+}
+
+func (vm *AddBrokerViewModel) SetTLSKeyFile(path string) {
+	vm.mu.Lock()
+	vm.tlsKeyFile = strings.TrimSpace(path)
+	vm.mu.Unlock()
+}
+
+func (vm *AddBrokerViewModel) SetTLSServerName(name string) {
+	vm.mu.Lock()
+	vm.tlsServerName = strings.TrimSpace(name)
+	vm.mu.Unlock()
 }
 
 func (vm *AddBrokerViewModel) GetAuthTypeOptions() []string {
@@ -262,6 +579,18 @@ func (vm *AddBrokerViewModel) GetPassword() string {
 	return vm.password
 }
 
+func (vm *AddBrokerViewModel) SetOAuthTokenURL(tokenURL string) {
+	vm.mu.Lock()
+	vm.oauthTokenURL = strings.TrimSpace(tokenURL)
+	vm.mu.Unlock()
+}
+
+func (vm *AddBrokerViewModel) GetOAuthTokenURL() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.oauthTokenURL
+}
+
 func (vm *AddBrokerViewModel) Validate() error {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
@@ -276,23 +605,155 @@ func (vm *AddBrokerViewModel) Validate() error {
 	if !bootstrapServersPattern.MatchString(servers) {
 		return errors.Join(ErrValidation, errors.New("invalid format, use host:port or host:port,host:port"))
 	}
+	if vm.tlsEnabled {
+		if err := validateTLSFiles(vm.tlsCAFile, vm.tlsCertFile, vm.tlsKeyFile); err != nil {
+			return err
+		}
+	}
+	if vm.authType == models.AuthSASL && vm.saslMechanism == models.SASLOAuthBearer {
+		if vm.oauthTokenURL == "" {
+			if strings.TrimSpace(vm.password) == "" && !vm.editing {
+				return errors.Join(ErrValidation, errors.New("token is required for OAUTHBEARER"))
+			}
+			return nil
+		}
+		u, err := url.Parse(vm.oauthTokenURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Join(ErrValidation, errors.New("token endpoint must be an http(s) URL"))
+		}
+		if strings.TrimSpace(vm.username) == "" || (strings.TrimSpace(vm.password) == "" && !vm.editing) {
+			return errors.Join(ErrValidation, errors.New("client ID and secret are required for the token endpoint"))
+		}
+		return nil
+	}
 	if vm.authType == models.AuthSASL {
 		if strings.TrimSpace(vm.username) == "" {
 			return errors.Join(ErrValidation, errors.New("username is required for SASL"))
 		}
-		if strings.TrimSpace(vm.password) == "" {
+		if strings.TrimSpace(vm.password) == "" && !vm.editing {
 			return errors.Join(ErrValidation, errors.New("password is required for SASL"))
 		}
 	}
 	return nil
 }
 
+func validateTLSFiles(caFile, certFile, keyFile string) error {
+	if certFile != "" && keyFile == "" {
+		return errors.Join(ErrValidation, errors.New("client key is required with a client certificate"))
+	}
+	for _, f := range []struct{ label, path string }{
+		{"CA bundle", caFile},
+		{"client certificate", certFile},
+		{"client key", keyFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(models.ExpandHome(f.path)); err != nil {
+			return errors.Join(ErrValidation, fmt.Errorf("%s %s: %w", f.label, f.path, err))
+		}
+	}
+	return nil
+}
+
+// TestConnection runs the connection test in the background and notifies a
+// "test" change once it finished
+func (vm *AddBrokerViewModel) TestConnection() {
+	config, err := vm.buildConfig()
+
+	vm.mu.Lock()
+	if vm.testing {
+		vm.mu.Unlock()
+		return
+	}
+	vm.testInfo = nil
+	vm.testErr = err
+	vm.testing = err == nil
+	test := vm.test
+	vm.mu.Unlock()
+	vm.notifyChange("test")
+
+	if err != nil || test == nil {
+		return
+	}
+
+	go func() {
+		info, err := test(config)
+
+		vm.mu.Lock()
+		vm.testing = false
+		vm.testErr = err
+		if err == nil {
+			vm.testInfo = &info
+		}
+		vm.mu.Unlock()
+		vm.notifyChange("test")
+	}()
+}
+
+func (vm *AddBrokerViewModel) IsTesting() bool {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.testing
+}
+
+func (vm *AddBrokerViewModel) TestPassed() bool {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.testInfo != nil
+}
+
+// RenderTestResult describes the cluster the test connected to, or why it
+// failed
+func (vm *AddBrokerViewModel) RenderTestResult() []string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	switch {
+	case vm.testing:
+		return []string{"Connecting to " + vm.bootstrapServers + "..."}
+	case vm.testErr != nil:
+		return strings.Split(vm.testErr.Error(), "\n")
+	case vm.testInfo == nil:
+		return nil
+	}
+
+	info := vm.testInfo
+	controller := "unknown"
+	if node, ok := info.Controller(); ok {
+		controller = fmt.Sprintf("%d (%s)", node.ID, node.Address())
+	}
+	version := info.Version
+	if version == "" {
+		version = "unknown"
+	}
+	return []string{
+		"Cluster ID:    " + info.ClusterID,
+		"Controller:    " + controller,
+		"Brokers:       " + strconv.Itoa(len(info.Brokers)),
+		"Kafka version: " + version,
+	}
+}
+
 func (vm *AddBrokerViewModel) Submit() error {
-	if err := vm.Validate(); err != nil {
+	config, err := vm.buildConfig()
+	if err != nil {
 		return err
 	}
 
+	if vm.onSubmit != nil {
+		vm.onSubmit(config)
+	}
+	return nil
+}
+
+func (vm *AddBrokerViewModel) buildConfig() (models.BrokerConfig, error) {
+	if err := vm.Validate(); err != nil {
+		return models.BrokerConfig{}, err
+	}
+
 	vm.mu.RLock()
+	defer vm.mu.RUnlock()
 	config := models.BrokerConfig{
 		Name:             strings.TrimSpace(vm.name),
 		BootstrapServers: strings.TrimSpace(vm.bootstrapServers),
@@ -301,12 +762,29 @@ func (vm *AddBrokerViewModel) Submit() error {
 		Username:         strings.TrimSpace(vm.username),
 		Password:         strings.TrimSpace(vm.password),
 	}
-	vm.mu.RUnlock()
-
-	if vm.onSubmit != nil {
-		vm.onSubmit(config)
+	if config.AuthType == models.AuthSASL {
+		config.SecretBackend = vm.secretBackend
 	}
-	return nil
+	if vm.tlsEnabled {
+		config.TLS = &models.TLSConfig{
+			Enabled:            true,
+			CAFile:             vm.tlsCAFile,
+			CertFile:           vm.tlsCertFile,
+			KeyFile:            vm.tlsKeyFile,
+			ServerName:         vm.tlsServerName,
+			InsecureSkipVerify: vm.tlsInsecure,
+		}
+		if config.TLS.CertFile == "" {
+			config.TLS.KeyFile = ""
+		}
+	}
+	if config.AuthType == models.AuthSASL && config.SASLMechanism == models.SASLOAuthBearer {
+		config.OAuthTokenURL = vm.oauthTokenURL
+		if config.OAuthTokenURL == "" {
+			config.Username = ""
+		}
+	}
+	return config, nil
 }
 
 func (vm *AddBrokerViewModel) Cancel() {
@@ -0,0 +1,187 @@
+package viewmodel
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepPartitionCount   = 0
+	StepPartitionConfirm = 1
+)
+
+// KeyedCheckFunc reports whether a topic carries keyed records
+type KeyedCheckFunc func() (bool, error)
+
+type AddPartitionsViewModel struct {
+	mu          sync.RWMutex
+	topic       string
+	current     int
+	count       string
+	total       int
+	keyed       bool
+	keyedErr    error
+	countErr    error
+	currentStep int
+	onChange    types.OnChangeFunc
+	checkKeyed  KeyedCheckFunc
+	onSubmit    func(topic string, total int)
+	onCancel    func()
+}
+
+func NewAddPartitionsViewModel(
+	topic string,
+	current int,
+	checkKeyed KeyedCheckFunc,
+	onSubmit func(string, int),
+	onCancel func(),
+) *AddPartitionsViewModel {
+	return &AddPartitionsViewModel{
+		topic:       topic,
+		current:     current,
+		count:       strconv.Itoa(current + 1),
+		currentStep: StepPartitionCount,
+		checkKeyed:  checkKeyed,
+		onSubmit:    onSubmit,
+		onCancel:    onCancel,
+	}
+}
+
+func (vm *AddPartitionsViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *AddPartitionsViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *AddPartitionsViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *AddPartitionsViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepPartitionCount:
+		title := fmt.Sprintf("New partition count for %s (currently %d):", vm.topic, vm.current)
+		if vm.countErr != nil {
+			title = fmt.Sprintf("%s, %s", strings.ReplaceAll(vm.countErr.Error(), "\n", ": "), title)
+		}
+		return title
+	case StepPartitionConfirm:
+		return fmt.Sprintf("Grow %s from %d to %d partitions? (Enter to apply, Esc to cancel)", vm.topic, vm.current, vm.total)
+	}
+	return ""
+}
+
+// NextStep moves to the confirmation once the count is valid and reports
+// true when the change is confirmed
+func (vm *AddPartitionsViewModel) NextStep() bool {
+	vm.mu.Lock()
+	switch vm.currentStep {
+	case StepPartitionCount:
+		total, err := vm.validateLocked()
+		vm.countErr = err
+		if err != nil {
+			vm.mu.Unlock()
+			return false
+		}
+		vm.total = total
+		vm.currentStep = StepPartitionConfirm
+	case StepPartitionConfirm:
+		vm.mu.Unlock()
+		return true // done, submit
+	}
+	vm.mu.Unlock()
+
+	vm.runKeyedCheck()
+	return false
+}
+
+func (vm *AddPartitionsViewModel) GetCount() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.count
+}
+
+func (vm *AddPartitionsViewModel) SetCount(count string) {
+	vm.mu.Lock()
+	vm.count = count
+	vm.mu.Unlock()
+}
+
+func (vm *AddPartitionsViewModel) validateLocked() (int, error) {
+	total, err := strconv.Atoi(strings.TrimSpace(vm.count))
+	if err != nil {
+		return 0, errors.Join(ErrValidation, errors.New("partition count must be an integer"))
+	}
+	if total <= vm.current {
+		return 0, errors.Join(ErrValidation, fmt.Errorf("partition count must be greater than %d", vm.current))
+	}
+	return total, nil
+}
+
+func (vm *AddPartitionsViewModel) runKeyedCheck() {
+	if vm.checkKeyed == nil {
+		return
+	}
+	keyed, err := vm.checkKeyed()
+
+	vm.mu.Lock()
+	vm.keyed = keyed
+	vm.keyedErr = err
+	vm.mu.Unlock()
+	vm.notifyChange("keyed")
+}
+
+// RenderWarning explains that keys hash to different partitions once the
+// count changes
+func (vm *AddPartitionsViewModel) RenderWarning() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	var sb strings.Builder
+	switch {
+	case vm.keyedErr != nil:
+		sb.WriteString(" Could not check for keyed records: " + strings.ReplaceAll(vm.keyedErr.Error(), "\n", ": ") + "\n")
+	case vm.keyed:
+		sb.WriteString(" Warning: this topic has keyed records.\n")
+	default:
+		sb.WriteString(" No keyed records found among the latest messages.\n")
+	}
+	sb.WriteString(" New records of an existing key may be written to a different partition,\n")
+	sb.WriteString(" so per-key ordering across old and new records is lost. Partitions cannot be removed again.\n")
+	return sb.String()
+}
+
+func (vm *AddPartitionsViewModel) GetWarningLineCount() int {
+	return 3
+}
+
+func (vm *AddPartitionsViewModel) Submit() error {
+	vm.mu.RLock()
+	topic := vm.topic
+	total := vm.total
+	vm.mu.RUnlock()
+
+	if vm.onSubmit != nil {
+		vm.onSubmit(topic, total)
+	}
+	return nil
+}
+
+func (vm *AddPartitionsViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
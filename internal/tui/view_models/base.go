@@ -1,6 +1,12 @@
 package viewmodel
 
-import "github.com/jurabek/lazykafka/internal/tui/types"
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
 
 type BaseViewModel interface {
 	SetOnChange(fn types.OnChangeFunc)
@@ -13,3 +19,31 @@ type BaseViewModel interface {
 	GetTitle() string
 	GetName() string
 }
+
+// formatTabs renders a tab header line with the active tab in brackets
+func formatTabs(names []string, active int) string {
+	tabs := make([]string, len(names))
+	for i, name := range names {
+		if i == active {
+			tabs[i] = "[" + name + "]"
+		} else {
+			tabs[i] = " " + name + " "
+		}
+	}
+	return " " + strings.Join(tabs, "  ") + "\n\n"
+}
+
+// openInEditor opens path in $EDITOR, or vi when it is unset, and waits for
+// the editor to exit
+func openInEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	cmd := exec.Command(editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
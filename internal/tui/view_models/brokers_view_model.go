@@ -3,7 +3,6 @@ package viewmodel
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sync"
 
@@ -22,6 +21,7 @@ type BrokersViewModel struct {
 	commandBindings    []*types.CommandBinding
 	gui                *gocui.Gui
 	onSelectionChanged BrokerSelectionChangedFunc
+	onConfigEdited     func()
 }
 
 func NewBrokersViewModel() *BrokersViewModel {
@@ -167,6 +167,28 @@ func (vm *BrokersViewModel) Load(brokers []models.Broker) {
 	vm.SetSelectedIndex(0)
 }
 
+// Reload replaces the brokers, keeping the selection on the broker with the
+// same name, and reselects it so its connection picks up config changes
+func (vm *BrokersViewModel) Reload(brokers []models.Broker) {
+	vm.mu.Lock()
+	index := 0
+	if vm.selectedIndex >= 0 && vm.selectedIndex < len(vm.brokers) {
+		selected := vm.brokers[vm.selectedIndex].Name
+		for i, b := range brokers {
+			if b.Name == selected {
+				index = i
+				break
+			}
+		}
+	}
+	vm.brokers = brokers
+	vm.selectedIndex = -1
+	vm.mu.Unlock()
+
+	vm.notifyChange(types.FieldItems)
+	vm.SetSelectedIndex(index)
+}
+
 func (vm *BrokersViewModel) AddBrokerConfig(config models.BrokerConfig) {
 	vm.mu.Lock()
 	newBroker := models.Broker{
@@ -183,6 +205,14 @@ func (vm *BrokersViewModel) SetGui(gui *gocui.Gui) {
 	vm.gui = gui
 }
 
+// SetOnConfigEdited registers the callback run after the external editor
+// exits, so brokers.json can be reloaded
+func (vm *BrokersViewModel) SetOnConfigEdited(fn func()) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.onConfigEdited = fn
+}
+
 func (vm *BrokersViewModel) OpenConfigInEditor() error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -191,15 +221,15 @@ func (vm *BrokersViewModel) OpenConfigInEditor() error {
 
 	configPath := filepath.Join(homeDir, ".lazykafka", "brokers.json")
 
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi"
+	if err := openInEditor(configPath); err != nil {
+		return err
 	}
 
-	cmd := exec.Command(editor, configPath)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd.Run()
+	vm.mu.RLock()
+	onConfigEdited := vm.onConfigEdited
+	vm.mu.RUnlock()
+	if onConfigEdited != nil {
+		onConfigEdited()
+	}
+	return nil
 }
@@ -0,0 +1,319 @@
+package viewmodel
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/kafka"
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+type ClusterTabType int
+
+const (
+	ClusterTabBrokers ClusterTabType = iota
+	ClusterTabAPIVersions
+)
+
+var clusterTabNames = []string{"Brokers", "API Versions"}
+
+// ClusterDetailViewModel backs the overview of the selected broker config's
+// cluster: its controller, brokers and supported API versions
+type ClusterDetailViewModel struct {
+	mu              sync.RWMutex
+	broker          *models.Broker
+	info            *models.ClusterInfo
+	loading         bool
+	activeTab       ClusterTabType
+	onChange        types.OnChangeFunc
+	commandBindings []*types.CommandBinding
+	kafkaClient     kafka.KafkaClient
+	onError         func(err error)
+}
+
+func NewClusterDetailViewModel() *ClusterDetailViewModel {
+	vm := &ClusterDetailViewModel{
+		activeTab: ClusterTabBrokers,
+	}
+	vm.initCommandBindings()
+	return vm
+}
+
+func (vm *ClusterDetailViewModel) initCommandBindings() {
+	prevTab := types.NewCommand(vm.PrevTab)
+	nextTab := types.NewCommand(vm.NextTab)
+	refresh := types.NewCommand(vm.Refresh)
+
+	vm.commandBindings = []*types.CommandBinding{
+		{Key: '[', Cmd: prevTab},
+		{Key: ']', Cmd: nextTab},
+		{Key: 'r', Cmd: refresh},
+	}
+}
+
+func (vm *ClusterDetailViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *ClusterDetailViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *ClusterDetailViewModel) GetSelectedIndex() int {
+	return 0
+}
+
+func (vm *ClusterDetailViewModel) SetSelectedIndex(index int) {}
+
+func (vm *ClusterDetailViewModel) GetItemCount() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.info == nil {
+		return 0
+	}
+	return len(vm.info.Brokers)
+}
+
+func (vm *ClusterDetailViewModel) GetCommandBindings() []*types.CommandBinding {
+	return vm.commandBindings
+}
+
+func (vm *ClusterDetailViewModel) GetDisplayItems() []string {
+	return []string{}
+}
+
+func (vm *ClusterDetailViewModel) GetTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.broker != nil {
+		return vm.broker.Name
+	}
+	return "Cluster"
+}
+
+func (vm *ClusterDetailViewModel) GetName() string {
+	return "cluster_detail"
+}
+
+func (vm *ClusterDetailViewModel) SetKafkaClient(client kafka.KafkaClient) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.kafkaClient = client
+}
+
+func (vm *ClusterDetailViewModel) SetOnError(fn func(err error)) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.onError = fn
+}
+
+// SetBroker shows the cluster of broker and loads it with the current client
+func (vm *ClusterDetailViewModel) SetBroker(broker *models.Broker) {
+	vm.mu.Lock()
+	vm.broker = broker
+	vm.info = nil
+	vm.mu.Unlock()
+
+	vm.load()
+}
+
+func (vm *ClusterDetailViewModel) Refresh() error {
+	vm.load()
+	return nil
+}
+
+func (vm *ClusterDetailViewModel) load() {
+	vm.mu.Lock()
+	broker := vm.broker
+	client := vm.kafkaClient
+	onError := vm.onError
+	vm.loading = broker != nil && client != nil
+	vm.mu.Unlock()
+
+	vm.notifyChange(types.FieldItems)
+	if broker == nil || client == nil {
+		return
+	}
+
+	go func() {
+		info, err := client.DescribeCluster(context.Background())
+
+		vm.mu.Lock()
+		if vm.broker != broker {
+			vm.mu.Unlock()
+			return
+		}
+		vm.loading = false
+		if err == nil || len(info.Brokers) > 0 {
+			vm.info = &info
+		}
+		vm.mu.Unlock()
+		vm.notifyChange(types.FieldItems)
+
+		if err != nil {
+			slog.Error("failed to describe cluster", slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+		}
+	}()
+}
+
+func (vm *ClusterDetailViewModel) GetActiveTab() ClusterTabType {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.activeTab
+}
+
+func (vm *ClusterDetailViewModel) SetActiveTab(tab ClusterTabType) {
+	vm.mu.Lock()
+	vm.activeTab = tab
+	vm.mu.Unlock()
+	vm.notifyChange(types.FieldSelectedIndex)
+}
+
+func (vm *ClusterDetailViewModel) NextTab() error {
+	vm.SetActiveTab((vm.GetActiveTab() + 1) % ClusterTabType(len(clusterTabNames)))
+	return nil
+}
+
+func (vm *ClusterDetailViewModel) PrevTab() error {
+	tab := vm.GetActiveTab() - 1
+	if tab < 0 {
+		tab = ClusterTabType(len(clusterTabNames) - 1)
+	}
+	vm.SetActiveTab(tab)
+	return nil
+}
+
+func (vm *ClusterDetailViewModel) RenderTabs() string {
+	return formatTabs(clusterTabNames, int(vm.GetActiveTab()))
+}
+
+// emptyMessage returns what to show instead of a table, if anything
+func (vm *ClusterDetailViewModel) emptyMessage() string {
+	switch {
+	case vm.broker == nil:
+		return "  Select a broker to view the cluster"
+	case vm.info == nil && vm.loading:
+		return "  Loading cluster metadata..."
+	case vm.info == nil:
+		return "  Not connected"
+	}
+	return ""
+}
+
+func (vm *ClusterDetailViewModel) RenderBrokersTable(width int) string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if msg := vm.emptyMessage(); msg != "" {
+		return msg
+	}
+
+	var sb strings.Builder
+	info := vm.info
+
+	controller := "-"
+	if info.ControllerID >= 0 {
+		controller = fmt.Sprintf("%d", info.ControllerID)
+	}
+	sb.WriteString(fmt.Sprintf("%-40s%-14s%-10s%-20s\n", "Cluster ID", "Controller", "Brokers", "Version"))
+	sb.WriteString(fmt.Sprintf("%-40s%-14s%-10d%-20s\n\n", info.ClusterID, controller, len(info.Brokers), formatVersion(info.Version)))
+
+	sb.WriteString(strings.Repeat("-", 70))
+	sb.WriteString("\n\n")
+
+	headers := []string{"ID", "Host", "Port", "Rack", "Leaders", "Replicas", "Version"}
+	colWidths := []int{8, 36, 8, 16, 10, 10, 16}
+
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for _, b := range info.Brokers {
+		id := fmt.Sprintf("%d", b.ID)
+		if b.ID == info.ControllerID {
+			id += "*"
+		}
+		rack := b.Rack
+		if rack == "" {
+			rack = "-"
+		}
+		sb.WriteString(fmt.Sprintf("%-*s%-*s%-*d%-*s%-*d%-*d%s\n",
+			colWidths[0], id,
+			colWidths[1], b.Host,
+			colWidths[2], b.Port,
+			colWidths[3], rack,
+			colWidths[4], b.Leaders,
+			colWidths[5], b.Replicas,
+			formatVersion(b.Version),
+		))
+	}
+	sb.WriteString("\n  * controller\n")
+
+	return sb.String()
+}
+
+func (vm *ClusterDetailViewModel) RenderAPIVersionsTable(width int) string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if msg := vm.emptyMessage(); msg != "" {
+		return msg
+	}
+	if len(vm.info.APIVersions) == 0 {
+		return "  No API versions reported"
+	}
+
+	var sb strings.Builder
+
+	headers := []string{"Key", "Request", "Min", "Max"}
+	colWidths := []int{8, 36, 8, 8}
+
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for _, v := range vm.info.APIVersions {
+		name := v.Name
+		if name == "" {
+			name = "Unknown"
+		}
+		sb.WriteString(fmt.Sprintf("%-*d%-*s%-*d%-*d\n",
+			colWidths[0], v.Key,
+			colWidths[1], name,
+			colWidths[2], v.MinVersion,
+			colWidths[3], v.MaxVersion,
+		))
+	}
+
+	return sb.String()
+}
+
+func formatVersion(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+	return version
+}
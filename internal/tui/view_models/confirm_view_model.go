@@ -0,0 +1,154 @@
+package viewmodel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepConfirmSelect = 0
+	StepConfirmInput  = 1
+)
+
+var ErrConfirmMismatch = errors.New("confirmation text does not match")
+
+// ConfirmViewModel backs a destructive action popup that only proceeds once
+// the user has typed the expected text. When options are given the user
+// first picks one of them.
+type ConfirmViewModel struct {
+	mu          sync.RWMutex
+	action      string
+	expected    string
+	options     []string
+	selected    int
+	currentStep int
+	err         error
+	onChange    types.OnChangeFunc
+	onConfirm   func(choice string)
+	onCancel    func()
+}
+
+func NewConfirmViewModel(action, expected string, options []string, onConfirm func(string), onCancel func()) *ConfirmViewModel {
+	step := StepConfirmInput
+	if len(options) > 0 {
+		step = StepConfirmSelect
+	}
+	return &ConfirmViewModel{
+		action:      action,
+		expected:    expected,
+		options:     options,
+		currentStep: step,
+		onConfirm:   onConfirm,
+		onCancel:    onCancel,
+	}
+}
+
+func (vm *ConfirmViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *ConfirmViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *ConfirmViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *ConfirmViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.currentStep == StepConfirmSelect {
+		return fmt.Sprintf("%s (↑↓ to select, Enter to confirm):", vm.action)
+	}
+
+	title := fmt.Sprintf("Type %q to %s:", vm.expected, vm.describeAction())
+	if vm.err != nil {
+		title = fmt.Sprintf("%s, %s", vm.err, title)
+	}
+	return title
+}
+
+func (vm *ConfirmViewModel) describeAction() string {
+	action := strings.ToLower(vm.action[:1]) + vm.action[1:]
+	if len(vm.options) > 0 {
+		return fmt.Sprintf("%s %s", action, vm.options[vm.selected])
+	}
+	return action
+}
+
+func (vm *ConfirmViewModel) GetOptions() []string {
+	return vm.options
+}
+
+func (vm *ConfirmViewModel) GetSelectedIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.selected
+}
+
+func (vm *ConfirmViewModel) MoveUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.selected > 0 {
+		vm.selected--
+	} else {
+		vm.selected = len(vm.options) - 1
+	}
+	vm.notifyChange(types.FieldSelectedIndex)
+}
+
+func (vm *ConfirmViewModel) MoveDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.selected < len(vm.options)-1 {
+		vm.selected++
+	} else {
+		vm.selected = 0
+	}
+	vm.notifyChange(types.FieldSelectedIndex)
+}
+
+func (vm *ConfirmViewModel) NextStep() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.currentStep == StepConfirmSelect {
+		vm.currentStep = StepConfirmInput
+	}
+}
+
+// Submit runs the confirmed action when typed matches the expected text
+func (vm *ConfirmViewModel) Submit(typed string) error {
+	vm.mu.Lock()
+	if strings.TrimSpace(typed) != vm.expected {
+		vm.err = ErrConfirmMismatch
+		vm.mu.Unlock()
+		return ErrConfirmMismatch
+	}
+	vm.err = nil
+	choice := ""
+	if len(vm.options) > 0 {
+		choice = vm.options[vm.selected]
+	}
+	vm.mu.Unlock()
+
+	if vm.onConfirm != nil {
+		vm.onConfirm(choice)
+	}
+	return nil
+}
+
+func (vm *ConfirmViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
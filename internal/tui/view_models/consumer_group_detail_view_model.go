@@ -1,28 +1,56 @@
 package viewmodel
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 
+	"github.com/jurabek/lazykafka/internal/kafka"
 	"github.com/jurabek/lazykafka/internal/models"
 	"github.com/jurabek/lazykafka/internal/tui/types"
 )
 
+type CGTabType int
+
+const (
+	CGTabOffsets CGTabType = iota
+	CGTabMembers
+)
+
+var cgTabNames = []string{"Offsets", "Members"}
+
 type ConsumerGroupDetailViewModel struct {
 	mu              sync.RWMutex
 	consumerGroup   *models.ConsumerGroup
 	offsets         []models.ConsumerGroupOffset
+	members         []models.ConsumerGroupMember
+	activeTab       CGTabType
 	onChange        types.OnChangeFunc
 	commandBindings []*types.CommandBinding
+	kafkaClient     kafka.KafkaClient
+	onError         func(err error)
 }
 
 func NewConsumerGroupDetailViewModel() *ConsumerGroupDetailViewModel {
-	vm := &ConsumerGroupDetailViewModel{}
-	vm.commandBindings = []*types.CommandBinding{}
+	vm := &ConsumerGroupDetailViewModel{
+		activeTab: CGTabOffsets,
+	}
+	vm.initCommandBindings()
 	return vm
 }
 
+func (vm *ConsumerGroupDetailViewModel) initCommandBindings() {
+	prevTab := types.NewCommand(vm.PrevTab)
+	nextTab := types.NewCommand(vm.NextTab)
+
+	vm.commandBindings = []*types.CommandBinding{
+		{Key: '[', Cmd: prevTab},
+		{Key: ']', Cmd: nextTab},
+	}
+}
+
 func (vm *ConsumerGroupDetailViewModel) SetOnChange(fn types.OnChangeFunc) {
 	vm.onChange = fn
 }
@@ -66,23 +94,61 @@ func (vm *ConsumerGroupDetailViewModel) GetName() string {
 	return "consumer_group_detail"
 }
 
+func (vm *ConsumerGroupDetailViewModel) SetKafkaClient(client kafka.KafkaClient) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.kafkaClient = client
+}
+
+func (vm *ConsumerGroupDetailViewModel) SetOnError(fn func(err error)) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.onError = fn
+}
+
 func (vm *ConsumerGroupDetailViewModel) SetConsumerGroup(cg *models.ConsumerGroup) {
 	vm.mu.Lock()
 	vm.consumerGroup = cg
+	vm.offsets = nil
+	vm.members = nil
+	client := vm.kafkaClient
+	onError := vm.onError
 	vm.mu.Unlock()
 
-	if cg != nil {
-		offsets := models.MockConsumerGroupOffsets(cg.Name)
+	if cg == nil || client == nil {
+		vm.notifyChange(types.FieldItems)
+		return
+	}
+
+	go func() {
+		offsets, err := client.GetConsumerGroupOffsets(context.Background(), cg.Name)
+		if err != nil {
+			slog.Error("failed to load consumer group offsets", slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+
+		members, err := client.GetConsumerGroupMembers(context.Background(), cg.Name)
+		if err != nil {
+			slog.Error("failed to load consumer group members", slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+		}
+
 		vm.mu.Lock()
+		if vm.consumerGroup == nil || vm.consumerGroup.Name != cg.Name {
+			// another group was selected while loading
+			vm.mu.Unlock()
+			return
+		}
 		vm.offsets = offsets
+		vm.members = members
 		vm.mu.Unlock()
-	} else {
-		vm.mu.Lock()
-		vm.offsets = nil
-		vm.mu.Unlock()
-	}
-
-	vm.notifyChange(types.FieldItems)
+		vm.notifyChange(types.FieldItems)
+	}()
 }
 
 func (vm *ConsumerGroupDetailViewModel) GetConsumerGroup() *models.ConsumerGroup {
@@ -91,6 +157,175 @@ func (vm *ConsumerGroupDetailViewModel) GetConsumerGroup() *models.ConsumerGroup
 	return vm.consumerGroup
 }
 
+func (vm *ConsumerGroupDetailViewModel) GetOffsetTopics() []string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	var topics []string
+	seen := make(map[string]bool)
+	for _, o := range vm.offsets {
+		if !seen[o.Topic] {
+			seen[o.Topic] = true
+			topics = append(topics, o.Topic)
+		}
+	}
+	return topics
+}
+
+// CanResetOffsets reports why offsets of the selected group cannot be reset
+func (vm *ConsumerGroupDetailViewModel) CanResetOffsets() error {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.consumerGroup == nil {
+		return types.ErrNoSelection
+	}
+	if vm.kafkaClient == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+	if vm.consumerGroup.State != models.GroupStateEmpty {
+		return fmt.Errorf("cannot reset offsets, group %q is %s (must be %s)",
+			vm.consumerGroup.Name, vm.consumerGroup.State, models.GroupStateEmpty)
+	}
+	return nil
+}
+
+func (vm *ConsumerGroupDetailViewModel) PreviewOffsetReset(ctx context.Context, spec models.OffsetResetSpec) ([]models.OffsetResetPreview, error) {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("no active kafka client")
+	}
+	return client.PreviewOffsetReset(ctx, spec)
+}
+
+func (vm *ConsumerGroupDetailViewModel) ResetOffsets(ctx context.Context, group string, previews []models.OffsetResetPreview) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	cg := vm.consumerGroup
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+
+	if err := client.ResetConsumerGroupOffsets(ctx, group, previews); err != nil {
+		return err
+	}
+
+	if cg != nil && cg.Name == group {
+		vm.SetConsumerGroup(cg)
+	}
+	return nil
+}
+
+func (vm *ConsumerGroupDetailViewModel) DeleteOffsets(ctx context.Context, group, topic string) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	cg := vm.consumerGroup
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+
+	if err := client.DeleteConsumerGroupOffsets(ctx, group, topic); err != nil {
+		return err
+	}
+
+	if cg != nil && cg.Name == group {
+		vm.SetConsumerGroup(cg)
+	}
+	return nil
+}
+
+func (vm *ConsumerGroupDetailViewModel) GetActiveTab() CGTabType {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.activeTab
+}
+
+func (vm *ConsumerGroupDetailViewModel) SetActiveTab(tab CGTabType) {
+	vm.mu.Lock()
+	vm.activeTab = tab
+	vm.mu.Unlock()
+	vm.notifyChange(types.FieldSelectedIndex)
+}
+
+func (vm *ConsumerGroupDetailViewModel) NextTab() error {
+	vm.SetActiveTab((vm.GetActiveTab() + 1) % CGTabType(len(cgTabNames)))
+	return nil
+}
+
+func (vm *ConsumerGroupDetailViewModel) PrevTab() error {
+	tab := vm.GetActiveTab() - 1
+	if tab < 0 {
+		tab = CGTabType(len(cgTabNames) - 1)
+	}
+	vm.SetActiveTab(tab)
+	return nil
+}
+
+func (vm *ConsumerGroupDetailViewModel) RenderTabs() string {
+	return formatTabs(cgTabNames, int(vm.GetActiveTab()))
+}
+
+func (vm *ConsumerGroupDetailViewModel) RenderMembersTable(width int) string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.consumerGroup == nil {
+		return "  Select a consumer group to view details"
+	}
+
+	if len(vm.members) == 0 {
+		return "  No active members"
+	}
+
+	var sb strings.Builder
+
+	headers := []string{"Client ID", "Host", "Partitions", "Member ID"}
+	colWidths := []int{30, 20, 12, 40}
+
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for _, m := range vm.members {
+		assigned := 0
+		for _, a := range m.Assignments {
+			assigned += len(a.Partitions)
+		}
+
+		memberID := m.MemberID
+		if m.InstanceID != "" {
+			memberID = fmt.Sprintf("%s (%s)", m.InstanceID, m.MemberID)
+		}
+
+		sb.WriteString(fmt.Sprintf("%-*s%-*s%-*d%s\n",
+			colWidths[0], m.ClientID,
+			colWidths[1], m.Host,
+			colWidths[2], assigned,
+			memberID,
+		))
+
+		for _, a := range m.Assignments {
+			sb.WriteString(fmt.Sprintf("    %s: %s\n", a.Topic, formatReplicas(a.Partitions)))
+		}
+	}
+
+	return sb.String()
+}
+
 func (vm *ConsumerGroupDetailViewModel) RenderOffsetsTable(width int) string {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
@@ -100,9 +335,23 @@ func (vm *ConsumerGroupDetailViewModel) RenderOffsetsTable(width int) string {
 	}
 
 	var sb strings.Builder
+	cg := vm.consumerGroup
+
+	sb.WriteString(fmt.Sprintf("%-20s%-20s%-20s%-20s\n", "State", "Protocol", "Coordinator", "Members"))
+	sb.WriteString(fmt.Sprintf("%-20s%-20s%-20d%-20d\n\n", cg.State, formatProtocol(cg), cg.Coordinator, cg.Members))
 
-	headers := []string{"Topic", "Partition", "Lag", "Offset"}
-	colWidths := []int{20, 12, 10, 12}
+	var totalLag int64
+	for _, o := range vm.offsets {
+		totalLag += o.Lag
+	}
+	sb.WriteString(fmt.Sprintf("%-20s\n", "Total Lag"))
+	sb.WriteString(fmt.Sprintf("%-20d\n\n", totalLag))
+
+	sb.WriteString(strings.Repeat("-", 70))
+	sb.WriteString("\n\n")
+
+	headers := []string{"Topic", "Partition", "Offset", "End Offset", "Lag"}
+	colWidths := []int{30, 12, 14, 14, 12}
 
 	for i, h := range headers {
 		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
@@ -116,13 +365,25 @@ func (vm *ConsumerGroupDetailViewModel) RenderOffsetsTable(width int) string {
 	sb.WriteString("\n")
 
 	for _, o := range vm.offsets {
-		sb.WriteString(fmt.Sprintf("%-*s%-*d%-*d%-*d\n",
+		sb.WriteString(fmt.Sprintf("%-*s%-*d%-*d%-*d%-*d\n",
 			colWidths[0], o.Topic,
 			colWidths[1], o.Partition,
-			colWidths[2], o.Lag,
-			colWidths[3], o.Offset,
+			colWidths[2], o.Offset,
+			colWidths[3], o.EndOffset,
+			colWidths[4], o.Lag,
 		))
 	}
 
 	return sb.String()
 }
+
+func formatProtocol(cg *models.ConsumerGroup) string {
+	switch {
+	case cg.ProtocolType == "":
+		return "-"
+	case cg.Protocol == "":
+		return cg.ProtocolType
+	default:
+		return fmt.Sprintf("%s/%s", cg.ProtocolType, cg.Protocol)
+	}
+}
@@ -1,10 +1,13 @@
 package viewmodel
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 
 	"github.com/jroimartin/gocui"
+	"github.com/jurabek/lazykafka/internal/kafka"
 	"github.com/jurabek/lazykafka/internal/models"
 	"github.com/jurabek/lazykafka/internal/tui/types"
 )
@@ -18,6 +21,11 @@ type ConsumerGroupsViewModel struct {
 	onChange           types.OnChangeFunc
 	commandBindings    []*types.CommandBinding
 	onSelectionChanged CGSelectionChangedFunc
+	kafkaClient        kafka.KafkaClient
+	// loadGen identifies the latest load, so a slow one finishing late does
+	// not replace the groups of another cluster
+	loadGen int
+	onError func(err error)
 }
 
 func NewConsumerGroupsViewModel() *ConsumerGroupsViewModel {
@@ -146,17 +154,99 @@ func (vm *ConsumerGroupsViewModel) GetSelectedConsumerGroup() *models.ConsumerGr
 
 func (vm *ConsumerGroupsViewModel) Load(consumerGroups []models.ConsumerGroup) {
 	vm.mu.Lock()
+	selected := 0
+	if vm.selectedIndex >= 0 && vm.selectedIndex < len(vm.consumerGroups) {
+		// keep the selection on the same group across reloads
+		name := vm.consumerGroups[vm.selectedIndex].Name
+		for i, cg := range consumerGroups {
+			if cg.Name == name {
+				selected = i
+				break
+			}
+		}
+	}
 	vm.consumerGroups = consumerGroups
 	vm.selectedIndex = -1
+	callback := vm.onSelectionChanged
 	vm.mu.Unlock()
 
 	vm.notifyChange(types.FieldItems)
-	vm.SetSelectedIndex(0)
+	if len(consumerGroups) == 0 {
+		if callback != nil {
+			callback(nil)
+		}
+		return
+	}
+	vm.SetSelectedIndex(selected)
+}
+
+func (vm *ConsumerGroupsViewModel) SetKafkaClient(client kafka.KafkaClient) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.kafkaClient = client
+	vm.loadGen++
+}
+
+func (vm *ConsumerGroupsViewModel) SetOnError(fn func(err error)) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.onError = fn
+}
+
+func (vm *ConsumerGroupsViewModel) LoadForBroker(_ *models.Broker) {
+	vm.loadConsumerGroupsAsync()
+}
+
+func (vm *ConsumerGroupsViewModel) Reload() {
+	vm.loadConsumerGroupsAsync()
 }
 
-func (vm *ConsumerGroupsViewModel) LoadForBroker(broker *models.Broker) {
+func (vm *ConsumerGroupsViewModel) loadConsumerGroupsAsync() {
+	vm.mu.Lock()
+	client := vm.kafkaClient
+	onError := vm.onError
+	vm.loadGen++
+	gen := vm.loadGen
+	vm.mu.Unlock()
+
+	if client == nil {
+		return
+	}
+
 	go func() {
-		consumerGroups := models.MockConsumerGroups()
+		consumerGroups, err := client.ListConsumerGroups(context.Background())
+
+		vm.mu.RLock()
+		stale := gen != vm.loadGen
+		vm.mu.RUnlock()
+		if stale {
+			return
+		}
+
+		if err != nil {
+			slog.Error("failed to load consumer groups", slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
 		vm.Load(consumerGroups)
 	}()
 }
+
+func (vm *ConsumerGroupsViewModel) DeleteConsumerGroup(ctx context.Context, group string) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+
+	if err := client.DeleteConsumerGroup(ctx, group); err != nil {
+		return err
+	}
+
+	vm.Reload()
+	return nil
+}
@@ -0,0 +1,327 @@
+package viewmodel
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepConfigName  = 0
+	StepConfigOp    = 1
+	StepConfigValue = 2
+	StepConfigDiff  = 3
+)
+
+// ConfigValidateFunc checks an alteration against the broker without applying it
+type ConfigValidateFunc func(alteration models.ConfigAlteration) error
+
+type EditTopicConfigViewModel struct {
+	mu          sync.RWMutex
+	topic       string
+	configs     []models.ConfigEntry
+	name        string
+	op          models.ConfigAlterOp
+	value       string
+	alteration  *models.ConfigAlteration
+	diffErr     error
+	currentStep int
+	onChange    types.OnChangeFunc
+	validate    ConfigValidateFunc
+	onSubmit    func(alteration models.ConfigAlteration)
+	onCancel    func()
+}
+
+func NewEditTopicConfigViewModel(
+	topic string,
+	configs []models.ConfigEntry,
+	validate ConfigValidateFunc,
+	onSubmit func(models.ConfigAlteration),
+	onCancel func(),
+) *EditTopicConfigViewModel {
+	return &EditTopicConfigViewModel{
+		topic:       topic,
+		configs:     configs,
+		name:        "retention.ms",
+		op:          models.ConfigSet,
+		currentStep: StepConfigName,
+		validate:    validate,
+		onSubmit:    onSubmit,
+		onCancel:    onCancel,
+	}
+}
+
+func (vm *EditTopicConfigViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *EditTopicConfigViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *EditTopicConfigViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *EditTopicConfigViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepConfigName:
+		return "Config of " + vm.topic + " to edit:"
+	case StepConfigOp:
+		return vm.name + " (↑↓ to select, Enter to confirm):"
+	case StepConfigValue:
+		switch {
+		case vm.op == models.ConfigAppend:
+			return "Values to append to " + vm.name + ":"
+		case vm.op == models.ConfigSubtract:
+			return "Values to remove from " + vm.name + ":"
+		case strings.HasSuffix(vm.name, ".ms"):
+			return "New value of " + vm.name + " (milliseconds, 7d, 168h or -1):"
+		}
+		return "New value of " + vm.name + ":"
+	case StepConfigDiff:
+		if vm.diffErr != nil {
+			return "Cannot apply change (Esc to cancel)"
+		}
+		return "Apply change? (Enter to apply, Esc to cancel)"
+	}
+	return ""
+}
+
+func (vm *EditTopicConfigViewModel) NextStep() bool {
+	vm.mu.Lock()
+
+	switch vm.currentStep {
+	case StepConfigName:
+		vm.currentStep = StepConfigOp
+	case StepConfigOp:
+		if vm.op == models.ConfigDelete {
+			vm.currentStep = StepConfigDiff
+		} else {
+			vm.currentStep = StepConfigValue
+		}
+	case StepConfigValue:
+		vm.currentStep = StepConfigDiff
+	case StepConfigDiff:
+		done := vm.diffErr == nil && vm.alteration != nil
+		vm.mu.Unlock()
+		return done // done, submit
+	}
+
+	step := vm.currentStep
+	vm.mu.Unlock()
+
+	if step == StepConfigDiff {
+		vm.runDiff()
+	}
+	return false
+}
+
+func (vm *EditTopicConfigViewModel) GetOpOptions() []string {
+	return []string{"Set", "Delete (revert to default)", "Append to list", "Subtract from list"}
+}
+
+func (vm *EditTopicConfigViewModel) GetSelectedOpIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.op)
+}
+
+func (vm *EditTopicConfigViewModel) MoveOpUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.op > models.ConfigSet {
+		vm.op--
+	} else {
+		vm.op = models.ConfigSubtract
+	}
+	vm.notifyChange("op")
+}
+
+func (vm *EditTopicConfigViewModel) MoveOpDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.op < models.ConfigSubtract {
+		vm.op++
+	} else {
+		vm.op = models.ConfigSet
+	}
+	vm.notifyChange("op")
+}
+
+func (vm *EditTopicConfigViewModel) GetName() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.name
+}
+
+func (vm *EditTopicConfigViewModel) SetName(name string) {
+	vm.mu.Lock()
+	vm.name = name
+	vm.mu.Unlock()
+}
+
+// GetDefaultValue prefills the value step with the current value when setting
+func (vm *EditTopicConfigViewModel) GetDefaultValue() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.op != models.ConfigSet {
+		return ""
+	}
+	if entry, ok := vm.findConfigLocked(vm.name); ok && !entry.Sensitive {
+		return entry.Value
+	}
+	return ""
+}
+
+func (vm *EditTopicConfigViewModel) SetValue(v string) {
+	vm.mu.Lock()
+	vm.value = v
+	vm.mu.Unlock()
+}
+
+func (vm *EditTopicConfigViewModel) findConfigLocked(name string) (models.ConfigEntry, bool) {
+	for _, c := range vm.configs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return models.ConfigEntry{}, false
+}
+
+func (vm *EditTopicConfigViewModel) BuildAlteration() (models.ConfigAlteration, error) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	alt := models.ConfigAlteration{Name: strings.TrimSpace(vm.name), Op: vm.op}
+	if alt.Name == "" {
+		return alt, errors.Join(ErrValidation, errors.New("config name is required"))
+	}
+	if _, ok := vm.findConfigLocked(alt.Name); !ok && len(vm.configs) > 0 {
+		return alt, errors.Join(ErrValidation, fmt.Errorf("unknown config %q", alt.Name))
+	}
+	if (alt.Op == models.ConfigAppend || alt.Op == models.ConfigSubtract) && !models.IsListConfig(alt.Name) {
+		return alt, errors.Join(ErrValidation, fmt.Errorf("%s is not a list config, %s is not supported", alt.Name, alt.Op))
+	}
+	if alt.Op == models.ConfigDelete {
+		return alt, nil
+	}
+
+	value := strings.TrimSpace(vm.value)
+	if value == "" {
+		return alt, errors.Join(ErrValidation, errors.New("value is required"))
+	}
+	value, err := models.NormalizeConfigValue(alt.Name, value)
+	if err != nil {
+		return alt, errors.Join(ErrValidation, err)
+	}
+	alt.Value = value
+
+	return alt, nil
+}
+
+func (vm *EditTopicConfigViewModel) runDiff() {
+	alt, err := vm.BuildAlteration()
+	if err == nil && vm.validate != nil {
+		err = vm.validate(alt)
+	}
+
+	vm.mu.Lock()
+	vm.alteration = nil
+	if err == nil {
+		vm.alteration = &alt
+	}
+	vm.diffErr = err
+	vm.mu.Unlock()
+	vm.notifyChange("diff")
+}
+
+func (vm *EditTopicConfigViewModel) GetDiffLineCount() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.diffErr != nil || vm.alteration == nil {
+		return 1
+	}
+	return 4
+}
+
+func (vm *EditTopicConfigViewModel) RenderDiff() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.diffErr != nil {
+		return fmt.Sprintf(" Error: %s", strings.ReplaceAll(vm.diffErr.Error(), "\n", ": "))
+	}
+	if vm.alteration == nil {
+		return ""
+	}
+
+	alt := *vm.alteration
+	entry, _ := vm.findConfigLocked(alt.Name)
+	newValue, ok := models.ApplyConfigAlteration(entry.Value, alt)
+
+	oldLine := formatConfigValue(entry, entry.Value)
+	newLine := "<inherited default>"
+	newSource := "default"
+	if ok {
+		newLine = formatConfigValue(entry, newValue)
+		newSource = models.ConfigSourceDynamicTopic.String()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(" %s (%s, %s -> %s)\n", alt.Name, alt.Op, entry.Source, newSource))
+	sb.WriteString(fmt.Sprintf(" - %s\n", oldLine))
+	sb.WriteString(fmt.Sprintf(" + %s\n", newLine))
+	return sb.String()
+}
+
+// formatConfigValue masks sensitive values and spells out millisecond durations
+func formatConfigValue(entry models.ConfigEntry, value string) string {
+	if entry.Sensitive {
+		return "******"
+	}
+	if value == "" {
+		return "<empty>"
+	}
+	if strings.HasSuffix(entry.Name, ".ms") {
+		if ms, err := strconv.ParseInt(value, 10, 64); err == nil && ms > 0 && ms < math.MaxInt64/int64(time.Millisecond) {
+			return fmt.Sprintf("%s (%s)", value, time.Duration(ms)*time.Millisecond)
+		}
+	}
+	return value
+}
+
+func (vm *EditTopicConfigViewModel) Submit() error {
+	vm.mu.RLock()
+	if vm.diffErr != nil {
+		err := vm.diffErr
+		vm.mu.RUnlock()
+		return err
+	}
+	alteration := vm.alteration
+	vm.mu.RUnlock()
+
+	if alteration != nil && vm.onSubmit != nil {
+		vm.onSubmit(*alteration)
+	}
+	return nil
+}
+
+func (vm *EditTopicConfigViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
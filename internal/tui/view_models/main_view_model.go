@@ -2,12 +2,15 @@ package viewmodel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
 
 	"github.com/jurabek/lazykafka/internal/kafka"
 	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/schemaregistry"
+	"github.com/jurabek/lazykafka/internal/secrets"
 	"github.com/jurabek/lazykafka/internal/tui/types"
 )
 
@@ -21,16 +24,40 @@ type MainViewModel struct {
 	topicDetailVM          *TopicDetailViewModel
 	consumerGroupDetailVM  *ConsumerGroupDetailViewModel
 	schemaRegistryDetailVM *SchemaRegistryDetailViewModel
+	clusterDetailVM        *ClusterDetailViewModel
 
 	onChange types.OnChangeFunc
 	ctx      context.Context
+	// streamCancel stops long-running streams bound to the current selection
+	streamCancel context.CancelFunc
 
 	clientFactory kafka.ClientFactory
 	activeClient  kafka.KafkaClient
 	brokerConfigs []models.BrokerConfig
 	onError       func(err error)
+
+	secretStores map[models.SecretBackend]secrets.SecretStore
+	// sessionPasswords holds secrets entered because the secret store could
+	// not provide them, so each broker is prompted for at most once
+	sessionPasswords map[string]string
+	// resolvedReferences holds what env: and cmd: references resolved to
+	resolvedReferences map[string]string
+	onSecretRequired   SecretPromptFunc
+
+	// connectMu serializes attaching clients, connectGen identifies the
+	// latest broker selection
+	connectMu  sync.Mutex
+	connectGen int
 }
 
+// SecretPromptFunc asks the user for the secret described by title and passes
+// it to onSubmit
+type SecretPromptFunc func(title string, onSubmit func(secret string))
+
+const passphraseTitle = "Passphrase for the encrypted secret file:"
+
+var errSecretMissing = errors.New("secret not available")
+
 func NewMainViewModel(
 	ctx context.Context,
 	configs []models.BrokerConfig,
@@ -44,9 +71,13 @@ func NewMainViewModel(
 		topicDetailVM:          NewTopicDetailViewModel(),
 		consumerGroupDetailVM:  NewConsumerGroupDetailViewModel(),
 		schemaRegistryDetailVM: NewSchemaRegistryDetailViewModel(),
+		clusterDetailVM:        NewClusterDetailViewModel(),
 		ctx:                    ctx,
 		clientFactory:          factory,
 		brokerConfigs:          configs,
+		secretStores:           make(map[models.SecretBackend]secrets.SecretStore),
+		sessionPasswords:       make(map[string]string),
+		resolvedReferences:     make(map[string]string),
 	}
 
 	vm.setupBrokerSelectionCallback()
@@ -80,6 +111,24 @@ func (vm *MainViewModel) SetOnError(fn func(err error)) {
 	vm.onError = fn
 	vm.topicsVM.SetOnError(fn)
 	vm.topicDetailVM.SetOnError(fn)
+	vm.consumerGroupsVM.SetOnError(fn)
+	vm.consumerGroupDetailVM.SetOnError(fn)
+	vm.clusterDetailVM.SetOnError(fn)
+	vm.schemaRegistryVM.SetOnError(fn)
+	vm.schemaRegistryDetailVM.SetOnError(fn)
+}
+
+// SetSecretStore registers the store used by brokers selecting backend
+func (vm *MainViewModel) SetSecretStore(backend models.SecretBackend, store secrets.SecretStore) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.secretStores[backend] = store
+}
+
+func (vm *MainViewModel) SetOnSecretRequired(fn SecretPromptFunc) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.onSecretRequired = fn
 }
 
 func (vm *MainViewModel) setupBrokerSelectionCallback() {
@@ -89,9 +138,17 @@ func (vm *MainViewModel) setupBrokerSelectionCallback() {
 	})
 }
 
-// setupTopicSelectionCallback registers callback for topic selection changes
+// setupTopicSelectionCallback registers callback for topic selection changes.
+// Reloading the topic list selects the same topic again, which only refreshes
+// its details so following it goes on.
 func (vm *MainViewModel) setupTopicSelectionCallback() {
 	vm.topicsVM.SetOnSelectionChanged(func(topic *models.Topic) {
+		current := vm.topicDetailVM.GetTopic()
+		if topic != nil && current != nil && current.Name == topic.Name {
+			vm.topicDetailVM.Refresh()
+			return
+		}
+		vm.topicDetailVM.SetStreamContext(vm.renewStreamContext())
 		vm.topicDetailVM.SetTopic(topic)
 	})
 }
@@ -110,6 +167,19 @@ func (vm *MainViewModel) setupSchemaRegistrySelectionCallback() {
 	})
 }
 
+// renewStreamContext cancels the streams started for the previous selection
+// and returns the context new streams should run under
+func (vm *MainViewModel) renewStreamContext() context.Context {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.streamCancel != nil {
+		vm.streamCancel()
+	}
+	ctx, cancel := context.WithCancel(vm.ctx)
+	vm.streamCancel = cancel
+	return ctx
+}
+
 func (vm *MainViewModel) getConfigForBroker(broker *models.Broker) *models.BrokerConfig {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
@@ -121,50 +191,438 @@ func (vm *MainViewModel) getConfigForBroker(broker *models.Broker) *models.Broke
 	return nil
 }
 
-// loadDependentData triggers async reload of all dependent ViewModels
-func (vm *MainViewModel) loadDependentData(broker *models.Broker) {
+// resolveCredentials fills in the secret brokers.json does not hold. env: and
+// cmd: references are resolved on first use, anything else comes from what
+// was entered this session or from the broker's secret store.
+func (vm *MainViewModel) resolveCredentials(config *models.BrokerConfig) error {
+	if config.AuthType != models.AuthSASL {
+		return nil
+	}
+	if secrets.IsReference(config.Password) {
+		password, err := vm.resolveReference(config.Password)
+		if err != nil {
+			return err
+		}
+		config.Password = password
+		return nil
+	}
+	if config.Password != "" {
+		return nil
+	}
+
+	vm.mu.RLock()
+	store := vm.secretStores[config.SecretBackend]
+	password, ok := vm.sessionPasswords[config.Name]
+	vm.mu.RUnlock()
+
+	if ok {
+		config.Password = password
+		return nil
+	}
+	if store == nil {
+		return errSecretMissing
+	}
+
+	username, password, err := store.GetCredentials(config.Name)
+	if errors.Is(err, secrets.ErrLocked) {
+		return err
+	}
+	if err != nil || password == "" {
+		slog.Warn("failed to read credentials from secret store",
+			slog.String("broker", config.Name),
+			slog.String("backend", config.SecretBackend.String()),
+			slog.Any("error", err))
+		return errSecretMissing
+	}
+
+	if config.Username == "" {
+		config.Username = username
+	}
+	config.Password = password
+	vm.mu.Lock()
+	vm.sessionPasswords[config.Name] = password
+	vm.mu.Unlock()
+	return nil
+}
+
+// promptForSecret asks for whatever resolveCredentials was missing and then
+// retries the broker selection
+func (vm *MainViewModel) promptForSecret(broker *models.Broker, config models.BrokerConfig, err error) {
+	vm.mu.RLock()
+	prompt := vm.onSecretRequired
+	store := vm.secretStores[config.SecretBackend]
+	onError := vm.onError
+	vm.mu.RUnlock()
+
+	unlocker, canUnlock := store.(secrets.Unlocker)
+	switch {
+	case errors.Is(err, secrets.ErrLocked) && canUnlock && prompt != nil:
+		prompt(passphraseTitle, func(passphrase string) {
+			if err := unlocker.Unlock(passphrase); err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				return
+			}
+			vm.loadDependentData(broker)
+		})
+	case errors.Is(err, errSecretMissing) && prompt != nil:
+		prompt(BrokerPasswordTitle(config), func(password string) {
+			vm.mu.Lock()
+			vm.sessionPasswords[config.Name] = password
+			vm.mu.Unlock()
+			vm.loadDependentData(broker)
+		})
+	default:
+		slog.Error("failed to resolve credentials", slog.String("broker", config.Name), slog.Any("error", err))
+		if onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// SaveCredentials stores the secret of a new broker in its secret backend,
+// asking for the passphrase first if the backend is locked. References are
+// not secret and stay in brokers.json.
+func (vm *MainViewModel) SaveCredentials(config models.BrokerConfig) {
+	if config.AuthType != models.AuthSASL || config.Password == "" || secrets.IsReference(config.Password) {
+		return
+	}
+
+	vm.mu.Lock()
+	vm.sessionPasswords[config.Name] = config.Password
+	store := vm.secretStores[config.SecretBackend]
+	prompt := vm.onSecretRequired
+	onError := vm.onError
+	vm.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+
+	err := store.SaveCredentials(config.Name, config.Username, config.Password)
+	if unlocker, ok := store.(secrets.Unlocker); ok && errors.Is(err, secrets.ErrLocked) && prompt != nil {
+		prompt(passphraseTitle, func(passphrase string) {
+			err := unlocker.Unlock(passphrase)
+			if err == nil {
+				err = store.SaveCredentials(config.Name, config.Username, config.Password)
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		})
+		return
+	}
+	if err != nil {
+		slog.Warn("failed to save credentials, the password is kept for this session only",
+			slog.String("broker", config.Name), slog.Any("error", err))
+	}
+}
+
+// TestConnection connects to the cluster described by config with a client of
+// its own and describes it. Errors carry a hint about the likely cause.
+func (vm *MainViewModel) TestConnection(ctx context.Context, config models.BrokerConfig) (models.ClusterInfo, error) {
+	vm.mu.RLock()
+	factory := vm.clientFactory
+	vm.mu.RUnlock()
+
+	if factory == nil {
+		return models.ClusterInfo{}, fmt.Errorf("no kafka client factory")
+	}
+	if err := vm.resolveCredentials(&config); err != nil {
+		if errors.Is(err, errSecretMissing) {
+			return models.ClusterInfo{}, fmt.Errorf("no stored secret for %s, enter one to test the connection", config.Name)
+		}
+		return models.ClusterInfo{}, err
+	}
+
+	client, err := factory.NewClient(config)
+	if err != nil {
+		return models.ClusterInfo{}, err
+	}
+	defer client.Close()
+
+	if err := client.Connect(ctx); err != nil {
+		return models.ClusterInfo{}, kafka.ExplainConnectError(err)
+	}
+	info, err := client.DescribeCluster(ctx)
+	if err != nil {
+		return models.ClusterInfo{}, kafka.ExplainConnectError(err)
+	}
+	return info, nil
+}
+
+// TestEditedConnection tests an edited broker like TestConnection. An empty
+// password keeps the current secret, which is still stored under the name and
+// backend of old.
+func (vm *MainViewModel) TestEditedConnection(ctx context.Context, old, updated models.BrokerConfig) (models.ClusterInfo, error) {
+	if updated.AuthType == models.AuthSASL && updated.Password == "" && old.AuthType == models.AuthSASL {
+		current := old
+		if err := vm.resolveCredentials(&current); err != nil {
+			if errors.Is(err, errSecretMissing) {
+				return models.ClusterInfo{}, fmt.Errorf("no stored secret for %s, enter one to test the connection", old.Name)
+			}
+			return models.ClusterInfo{}, err
+		}
+		updated.Password = current.Password
+		if updated.Username == "" {
+			updated.Username = current.Username
+		}
+	}
+	return vm.TestConnection(ctx, updated)
+}
+
+// UpdateCredentials stores the secret of an edited broker. An empty password
+// keeps the current secret, which moves along when the broker is renamed or
+// switches secret backend.
+func (vm *MainViewModel) UpdateCredentials(old, updated models.BrokerConfig) {
+	if updated.AuthType != models.AuthSASL {
+		vm.DeleteCredentials(old)
+		return
+	}
+
+	moved := old.Name != updated.Name || old.SecretBackend != updated.SecretBackend
+	if updated.Password == "" {
+		if !moved || old.AuthType != models.AuthSASL {
+			return
+		}
+		current := old
+		if err := vm.resolveCredentials(&current); err != nil {
+			slog.Warn("failed to read the current secret, it was not moved",
+				slog.String("broker", old.Name), slog.Any("error", err))
+			return
+		}
+		updated.Password = current.Password
+	}
+
+	if moved {
+		vm.DeleteCredentials(old)
+	}
+	vm.SaveCredentials(updated)
+}
+
+// DeleteCredentials forgets the secret of config in this session and in its
+// secret backend
+func (vm *MainViewModel) DeleteCredentials(config models.BrokerConfig) {
+	vm.mu.Lock()
+	delete(vm.sessionPasswords, config.Name)
+	store := vm.secretStores[config.SecretBackend]
+	vm.mu.Unlock()
+
+	if config.AuthType != models.AuthSASL || store == nil || secrets.IsReference(config.Password) {
+		return
+	}
+	if err := store.DeleteCredentials(config.Name); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+		slog.Warn("failed to delete credentials",
+			slog.String("broker", config.Name),
+			slog.String("backend", config.SecretBackend.String()),
+			slog.Any("error", err))
+	}
+}
+
+// SetBrokerConfigs replaces every broker config, e.g. after brokers.json was
+// edited. The selected broker stays selected by name and is reconnected with
+// its new config.
+func (vm *MainViewModel) SetBrokerConfigs(configs []models.BrokerConfig) {
+	vm.mu.Lock()
+	vm.brokerConfigs = configs
+	vm.mu.Unlock()
+
+	if len(configs) == 0 {
+		vm.disconnect()
+	}
+	vm.brokersVM.Reload(configsToBrokers(configs))
+}
+
+// disconnect closes the active client once no broker is left to select
+func (vm *MainViewModel) disconnect() {
+	vm.mu.Lock()
+	// drop connections still being made
+	vm.connectGen++
+	vm.mu.Unlock()
+
+	vm.connectMu.Lock()
+	defer vm.connectMu.Unlock()
+	vm.clusterDetailVM.SetBroker(nil)
+	vm.detach()
+}
+
+// detach closes the active client and empties every panel showing data of its
+// cluster, so nothing is left backed by a closed client
+func (vm *MainViewModel) detach() {
+	vm.topicDetailVM.SetStreamContext(vm.renewStreamContext())
+
 	vm.mu.Lock()
 	if vm.activeClient != nil {
 		vm.activeClient.Close()
 		vm.activeClient = nil
 	}
+	vm.mu.Unlock()
+
+	vm.topicsVM.SetKafkaClient(nil)
+	vm.topicDetailVM.SetKafkaClient(nil)
+	vm.consumerGroupsVM.SetKafkaClient(nil)
+	vm.consumerGroupDetailVM.SetKafkaClient(nil)
+	vm.clusterDetailVM.SetKafkaClient(nil)
+	vm.schemaRegistryVM.SetSchemaRegistryClient(nil)
+	vm.schemaRegistryDetailVM.SetSchemaRegistryClient(nil)
+	vm.topicDetailVM.SetSchemaRegistryClient(nil)
+
+	vm.topicsVM.Load(nil)
+	vm.consumerGroupsVM.Load(nil)
+	vm.consumerGroupDetailVM.SetConsumerGroup(nil)
+	vm.schemaRegistryVM.Load(nil)
+}
+
+// loadDependentData connects to broker and reloads every panel showing its
+// cluster. Secrets are resolved and the connection is made off the UI thread,
+// as cmd: references may take a while. A broker selected meanwhile wins.
+func (vm *MainViewModel) loadDependentData(broker *models.Broker) {
+	vm.mu.Lock()
+	vm.connectGen++
+	gen := vm.connectGen
+	vm.mu.Unlock()
+
+	go vm.connect(broker, gen)
+}
+
+func (vm *MainViewModel) connect(broker *models.Broker, gen int) {
+	vm.mu.RLock()
 	factory := vm.clientFactory
 	onError := vm.onError
-	vm.mu.Unlock()
+	vm.mu.RUnlock()
 
-	config := vm.getConfigForBroker(broker)
-	if config == nil || factory == nil {
-		return
+	var (
+		resolved     models.BrokerConfig
+		client       kafka.KafkaClient
+		registry     schemaregistry.Client
+		credErr      error
+		connectErr   error
+		registryErr  error
+		configExists bool
+	)
+	if config := vm.getConfigForBroker(broker); config != nil && factory != nil {
+		configExists = true
+		resolved = *config
+		credErr = vm.resolveCredentials(&resolved)
+		if credErr == nil {
+			client, connectErr = dial(vm.ctx, factory, resolved)
+		}
+		if client != nil && resolved.HasSchemaRegistry() {
+			registry, registryErr = vm.newSchemaRegistryClient(*resolved.SchemaRegistry)
+		}
 	}
 
-	client, err := factory.NewClient(*config)
-	if err != nil {
-		slog.Error("failed to create kafka client", slog.Any("error", err))
-		if onError != nil {
-			onError(err)
+	vm.connectMu.Lock()
+	defer vm.connectMu.Unlock()
+
+	vm.mu.RLock()
+	stale := gen != vm.connectGen
+	vm.mu.RUnlock()
+	if stale {
+		if client != nil {
+			client.Close()
 		}
 		return
 	}
 
-	if err := client.Connect(vm.ctx); err != nil {
-		slog.Error("failed to connect to kafka", slog.Any("error", err))
+	vm.clusterDetailVM.SetBroker(broker)
+	switch {
+	case !configExists:
+		vm.detach()
+		return
+	case credErr != nil:
+		vm.detach()
+		vm.promptForSecret(broker, resolved, credErr)
+		return
+	case connectErr != nil:
+		vm.detach()
+		slog.Error("failed to connect to kafka", slog.Any("error", connectErr))
 		if onError != nil {
-			onError(err)
+			onError(connectErr)
 		}
-		client.Close()
 		return
 	}
 
+	vm.topicDetailVM.SetStreamContext(vm.renewStreamContext())
+
 	vm.mu.Lock()
+	if vm.activeClient != nil {
+		vm.activeClient.Close()
+	}
 	vm.activeClient = client
 	vm.mu.Unlock()
 
 	vm.topicsVM.SetKafkaClient(client)
 	vm.topicDetailVM.SetKafkaClient(client)
+	vm.consumerGroupsVM.SetKafkaClient(client)
+	vm.consumerGroupDetailVM.SetKafkaClient(client)
+	vm.clusterDetailVM.SetKafkaClient(client)
+
+	if registryErr != nil {
+		slog.Error("failed to create schema registry client", slog.Any("error", registryErr))
+		if onError != nil {
+			onError(registryErr)
+		}
+	}
+	vm.schemaRegistryVM.SetSchemaRegistryClient(registry)
+	vm.schemaRegistryDetailVM.SetSchemaRegistryClient(registry)
+	vm.topicDetailVM.SetSchemaRegistryClient(registry)
 
 	vm.topicsVM.LoadForBroker(broker)
 	vm.consumerGroupsVM.LoadForBroker(broker)
 	vm.schemaRegistryVM.LoadForBroker(broker)
+	_ = vm.clusterDetailVM.Refresh()
+}
+
+// dial creates a client for config and connects it. Errors carry a hint about
+// the likely cause.
+func dial(ctx context.Context, factory kafka.ClientFactory, config models.BrokerConfig) (kafka.KafkaClient, error) {
+	client, err := factory.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Connect(ctx); err != nil {
+		client.Close()
+		return nil, kafka.ExplainConnectError(err)
+	}
+	return client, nil
+}
+
+// newSchemaRegistryClient creates the registry client of a broker, resolving
+// env: and cmd: references in its secrets first
+func (vm *MainViewModel) newSchemaRegistryClient(config models.SchemaRegistryConfig) (schemaregistry.Client, error) {
+	for _, secret := range []*string{&config.Password, &config.BearerToken} {
+		if !secrets.IsReference(*secret) {
+			continue
+		}
+		value, err := vm.resolveReference(*secret)
+		if err != nil {
+			return nil, fmt.Errorf("schema registry: %w", err)
+		}
+		*secret = value
+	}
+	return schemaregistry.NewClient(config)
+}
+
+// resolveReference resolves an env: or cmd: reference once per session, so
+// commands asking for a pinentry or otherwise slow only run on first use
+func (vm *MainViewModel) resolveReference(ref string) (string, error) {
+	vm.mu.RLock()
+	value, ok := vm.resolvedReferences[ref]
+	vm.mu.RUnlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := secrets.ResolveReference(vm.ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	vm.mu.Lock()
+	vm.resolvedReferences[ref] = value
+	vm.mu.Unlock()
+	return value, nil
 }
 
 func (vm *MainViewModel) SetOnChange(fn types.OnChangeFunc) {
@@ -199,6 +657,10 @@ func (vm *MainViewModel) SchemaRegistryDetailVM() *SchemaRegistryDetailViewModel
 	return vm.schemaRegistryDetailVM
 }
 
+func (vm *MainViewModel) ClusterDetailVM() *ClusterDetailViewModel {
+	return vm.clusterDetailVM
+}
+
 func (vm *MainViewModel) AddBrokerConfig(config models.BrokerConfig) {
 	vm.mu.Lock()
 	defer vm.mu.Unlock()
@@ -217,3 +679,16 @@ func (vm *MainViewModel) CreateTopic(ctx context.Context, config models.TopicCon
 
 	return client.CreateTopic(ctx, config)
 }
+
+func (vm *MainViewModel) ProduceMessages(ctx context.Context, records []models.ProduceRecord) ([]models.ProduceResult, error) {
+	vm.mu.RLock()
+	client := vm.activeClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("no active kafka client")
+	}
+	slog.Info("produce messages", slog.Int("count", len(records)))
+
+	return client.ProduceMessages(ctx, records)
+}
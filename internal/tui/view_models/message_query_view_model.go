@@ -0,0 +1,212 @@
+package viewmodel
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepMessageStart     = 0
+	StepMessageValue     = 1
+	StepMessagePartition = 2
+)
+
+// MessageQueryViewModel backs the popup choosing where the message browser
+// starts reading a topic
+type MessageQueryViewModel struct {
+	mu          sync.RWMutex
+	topic       string
+	start       models.MessageStartPosition
+	value       string
+	partition   string
+	currentStep int
+	onChange    types.OnChangeFunc
+	onSubmit    func(query models.MessageQuery)
+	onCancel    func()
+}
+
+func NewMessageQueryViewModel(topic string, onSubmit func(models.MessageQuery), onCancel func()) *MessageQueryViewModel {
+	return &MessageQueryViewModel{
+		topic:       topic,
+		start:       models.StartLatest,
+		currentStep: StepMessageStart,
+		onSubmit:    onSubmit,
+		onCancel:    onCancel,
+	}
+}
+
+func (vm *MessageQueryViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *MessageQueryViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *MessageQueryViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *MessageQueryViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepMessageStart:
+		return "Read " + vm.topic + " from (↑↓ to select, Enter to confirm):"
+	case StepMessageValue:
+		switch vm.start {
+		case models.StartLatest:
+			return "Number of messages:"
+		case models.StartOffset:
+			return "Offset:"
+		case models.StartTimestamp:
+			return "Timestamp (RFC3339, 2006-01-02 15:04:05 or unix millis):"
+		}
+	case StepMessagePartition:
+		return "Partition (leave empty for all):"
+	}
+	return ""
+}
+
+func (vm *MessageQueryViewModel) NextStep() bool {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	switch vm.currentStep {
+	case StepMessageStart:
+		if vm.start == models.StartEarliest {
+			vm.currentStep = StepMessagePartition
+		} else {
+			vm.currentStep = StepMessageValue
+		}
+	case StepMessageValue:
+		vm.currentStep = StepMessagePartition
+	case StepMessagePartition:
+		return true // done, submit
+	}
+	return false
+}
+
+func (vm *MessageQueryViewModel) GetStartOptions() []string {
+	return []string{"Earliest", "Latest N", "Offset", "Timestamp"}
+}
+
+func (vm *MessageQueryViewModel) GetSelectedStartIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.start)
+}
+
+func (vm *MessageQueryViewModel) MoveStartUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.start > 0 {
+		vm.start--
+	} else {
+		vm.start = models.StartTimestamp
+	}
+	vm.notifyChange("start")
+}
+
+func (vm *MessageQueryViewModel) MoveStartDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.start < models.StartTimestamp {
+		vm.start++
+	} else {
+		vm.start = models.StartEarliest
+	}
+	vm.notifyChange("start")
+}
+
+func (vm *MessageQueryViewModel) GetDefaultValue() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.start == models.StartLatest {
+		return strconv.Itoa(messagePageSize)
+	}
+	return ""
+}
+
+func (vm *MessageQueryViewModel) SetValue(v string) {
+	vm.mu.Lock()
+	vm.value = v
+	vm.mu.Unlock()
+}
+
+func (vm *MessageQueryViewModel) SetPartition(p string) {
+	vm.mu.Lock()
+	vm.partition = p
+	vm.mu.Unlock()
+}
+
+func (vm *MessageQueryViewModel) BuildQuery() (models.MessageQuery, error) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	query := models.MessageQuery{
+		Topic:     vm.topic,
+		Partition: models.AllPartitions,
+		Start:     vm.start,
+		Limit:     messagePageSize,
+	}
+
+	value := strings.TrimSpace(vm.value)
+	switch vm.start {
+	case models.StartLatest:
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return query, errors.Join(ErrValidation, errors.New("number of messages must be a positive integer"))
+		}
+		query.Limit = n
+	case models.StartOffset:
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || offset < 0 {
+			return query, errors.Join(ErrValidation, errors.New("offset must be a non-negative integer"))
+		}
+		query.Offset = offset
+	case models.StartTimestamp:
+		ts, err := models.ParseTimestamp(value)
+		if err != nil {
+			return query, errors.Join(ErrValidation, err)
+		}
+		query.Timestamp = ts
+	}
+
+	if partition := strings.TrimSpace(vm.partition); partition != "" {
+		p, err := strconv.Atoi(partition)
+		if err != nil || p < 0 {
+			return query, errors.Join(ErrValidation, errors.New("partition must be a non-negative integer"))
+		}
+		query.Partition = p
+	}
+
+	return query, nil
+}
+
+func (vm *MessageQueryViewModel) Submit() error {
+	query, err := vm.BuildQuery()
+	if err != nil {
+		return err
+	}
+
+	if vm.onSubmit != nil {
+		vm.onSubmit(query)
+	}
+	return nil
+}
+
+func (vm *MessageQueryViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
@@ -0,0 +1,68 @@
+package viewmodel
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/models"
+)
+
+// PasswordViewModel backs the masked popup asking for a secret the secret
+// stores could not provide, such as a broker password or a file passphrase
+type PasswordViewModel struct {
+	mu       sync.RWMutex
+	title    string
+	err      error
+	onSubmit func(password string)
+	onCancel func()
+}
+
+func NewPasswordViewModel(title string, onSubmit func(string), onCancel func()) *PasswordViewModel {
+	return &PasswordViewModel{
+		title:    title,
+		onSubmit: onSubmit,
+		onCancel: onCancel,
+	}
+}
+
+// BrokerPasswordTitle describes the secret SASL needs for config
+func BrokerPasswordTitle(config models.BrokerConfig) string {
+	label := "Password"
+	if config.SASLMechanism == models.SASLOAuthBearer {
+		label = "Token or client secret"
+	}
+	return label + " for " + config.Name + " (kept for this session):"
+}
+
+func (vm *PasswordViewModel) GetTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	title := vm.title
+	if vm.err != nil {
+		title += " " + vm.err.Error()
+	}
+	return title
+}
+
+func (vm *PasswordViewModel) Submit(password string) error {
+	password = strings.TrimSpace(password)
+	if password == "" {
+		vm.mu.Lock()
+		vm.err = errors.New("cannot be empty")
+		vm.mu.Unlock()
+		return errors.Join(ErrValidation, vm.err)
+	}
+
+	if vm.onSubmit != nil {
+		vm.onSubmit(password)
+	}
+	return nil
+}
+
+func (vm *PasswordViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
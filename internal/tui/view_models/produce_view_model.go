@@ -0,0 +1,256 @@
+package viewmodel
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepProduceSource       = 0
+	StepProduceKey          = 1
+	StepProduceValue        = 2
+	StepProduceFile         = 3
+	StepProduceKeySeparator = 4
+	StepProduceHeaders      = 5
+	StepProducePartition    = 6
+)
+
+type ProduceSource int
+
+const (
+	ProduceSingle ProduceSource = iota
+	ProduceFromFile
+)
+
+// ProduceViewModel backs the popup sending either a single record or the
+// newline-delimited records of a file to a topic
+type ProduceViewModel struct {
+	mu           sync.RWMutex
+	topic        string
+	source       ProduceSource
+	key          string
+	value        string
+	filePath     string
+	keySeparator string
+	headers      string
+	partition    string
+	currentStep  int
+	onChange     types.OnChangeFunc
+	onSubmit     func(records []models.ProduceRecord)
+	onCancel     func()
+}
+
+func NewProduceViewModel(topic string, onSubmit func([]models.ProduceRecord), onCancel func()) *ProduceViewModel {
+	return &ProduceViewModel{
+		topic:       topic,
+		source:      ProduceSingle,
+		currentStep: StepProduceSource,
+		onSubmit:    onSubmit,
+		onCancel:    onCancel,
+	}
+}
+
+func (vm *ProduceViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *ProduceViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *ProduceViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *ProduceViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepProduceSource:
+		return "Produce to " + vm.topic + " (↑↓ to select, Enter to confirm):"
+	case StepProduceKey:
+		return "Key (leave empty for a null key):"
+	case StepProduceValue:
+		return "Value:"
+	case StepProduceFile:
+		return "File with one record per line:"
+	case StepProduceKeySeparator:
+		return "Key separator (leave empty to use whole lines as values):"
+	case StepProduceHeaders:
+		return "Headers (key=value,key2=value2 or leave empty):"
+	case StepProducePartition:
+		return "Partition (leave empty for the default partitioner):"
+	}
+	return ""
+}
+
+func (vm *ProduceViewModel) NextStep() bool {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	switch vm.currentStep {
+	case StepProduceSource:
+		if vm.source == ProduceFromFile {
+			vm.currentStep = StepProduceFile
+		} else {
+			vm.currentStep = StepProduceKey
+		}
+	case StepProduceKey, StepProduceFile:
+		vm.currentStep++
+	case StepProduceValue, StepProduceKeySeparator:
+		vm.currentStep = StepProduceHeaders
+	case StepProduceHeaders:
+		vm.currentStep = StepProducePartition
+	case StepProducePartition:
+		return true // done, submit
+	}
+	return false
+}
+
+func (vm *ProduceViewModel) GetSourceOptions() []string {
+	return []string{"Single record", "From file"}
+}
+
+func (vm *ProduceViewModel) GetSelectedSourceIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.source)
+}
+
+func (vm *ProduceViewModel) MoveSourceUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.source > ProduceSingle {
+		vm.source--
+	} else {
+		vm.source = ProduceFromFile
+	}
+	vm.notifyChange("source")
+}
+
+func (vm *ProduceViewModel) MoveSourceDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.source < ProduceFromFile {
+		vm.source++
+	} else {
+		vm.source = ProduceSingle
+	}
+	vm.notifyChange("source")
+}
+
+func (vm *ProduceViewModel) SetKey(key string) {
+	vm.mu.Lock()
+	vm.key = key
+	vm.mu.Unlock()
+}
+
+func (vm *ProduceViewModel) SetValue(value string) {
+	vm.mu.Lock()
+	vm.value = value
+	vm.mu.Unlock()
+}
+
+func (vm *ProduceViewModel) SetFilePath(path string) {
+	vm.mu.Lock()
+	vm.filePath = path
+	vm.mu.Unlock()
+}
+
+func (vm *ProduceViewModel) SetKeySeparator(sep string) {
+	vm.mu.Lock()
+	vm.keySeparator = sep
+	vm.mu.Unlock()
+}
+
+func (vm *ProduceViewModel) SetHeaders(headers string) {
+	vm.mu.Lock()
+	vm.headers = headers
+	vm.mu.Unlock()
+}
+
+func (vm *ProduceViewModel) SetPartition(p string) {
+	vm.mu.Lock()
+	vm.partition = p
+	vm.mu.Unlock()
+}
+
+// BuildRecords validates the input and returns the records to produce,
+// reading the file when producing from one
+func (vm *ProduceViewModel) BuildRecords() ([]models.ProduceRecord, error) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	partition := models.DefaultPartitioner
+	if p := strings.TrimSpace(vm.partition); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, errors.Join(ErrValidation, errors.New("partition must be a non-negative integer"))
+		}
+		partition = n
+	}
+
+	headers, err := models.ParseHeaders(vm.headers)
+	if err != nil {
+		return nil, errors.Join(ErrValidation, err)
+	}
+
+	var records []models.ProduceRecord
+	if vm.source == ProduceFromFile {
+		path := strings.TrimSpace(vm.filePath)
+		if path == "" {
+			return nil, errors.Join(ErrValidation, errors.New("file path is required"))
+		}
+		f, err := os.Open(models.ExpandHome(path))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		records, err = models.ParseRecordLines(f, vm.keySeparator)
+		if err != nil {
+			return nil, errors.Join(ErrValidation, err)
+		}
+	} else {
+		record := models.ProduceRecord{Value: []byte(vm.value)}
+		if vm.key != "" {
+			record.Key = []byte(vm.key)
+		}
+		records = []models.ProduceRecord{record}
+	}
+
+	for i := range records {
+		records[i].Topic = vm.topic
+		records[i].Partition = partition
+		records[i].Headers = headers
+	}
+	return records, nil
+}
+
+func (vm *ProduceViewModel) Submit() error {
+	records, err := vm.BuildRecords()
+	if err != nil {
+		return err
+	}
+
+	if vm.onSubmit != nil {
+		vm.onSubmit(records)
+	}
+	return nil
+}
+
+func (vm *ProduceViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
@@ -0,0 +1,431 @@
+package viewmodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/schemaregistry"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepRegisterSubject = 0
+	StepRegisterType    = 1
+	StepRegisterSource  = 2
+	StepRegisterFile    = 3
+	StepRegisterMode    = 4
+	StepRegisterResult  = 5
+)
+
+type RegisterSource int
+
+const (
+	RegisterFromEditor RegisterSource = iota
+	RegisterFromFile
+)
+
+type CompatibilityCheckFunc func(schema models.SchemaRegistry) (models.CompatibilityResult, error)
+
+var schemaTypes = []string{schemaregistry.SchemaTypeAvro, schemaregistry.SchemaTypeProtobuf, schemaregistry.SchemaTypeJSON}
+
+// RegisterSchemaViewModel backs the popup registering a new version of a
+// subject. The schema is written in $EDITOR, starting from the latest
+// version, or read from a file. It is always checked against the latest
+// version first, and only checked in check only mode.
+type RegisterSchemaViewModel struct {
+	mu          sync.RWMutex
+	subject     string
+	schemaType  int
+	source      RegisterSource
+	filePath    string
+	checkOnly   bool
+	latest      *models.SchemaRegistry
+	schema      string
+	checked     models.SchemaRegistry
+	result      models.CompatibilityResult
+	checkErr    error
+	currentStep int
+	onChange    types.OnChangeFunc
+	check       CompatibilityCheckFunc
+	onSubmit    func(schema models.SchemaRegistry)
+	onCancel    func()
+}
+
+// NewRegisterSchemaViewModel prefills the popup with latest, the latest
+// version of the selected subject, which may be nil
+func NewRegisterSchemaViewModel(
+	latest *models.SchemaRegistry,
+	check CompatibilityCheckFunc,
+	onSubmit func(models.SchemaRegistry),
+	onCancel func(),
+) *RegisterSchemaViewModel {
+	vm := &RegisterSchemaViewModel{
+		currentStep: StepRegisterSubject,
+		latest:      latest,
+		check:       check,
+		onSubmit:    onSubmit,
+		onCancel:    onCancel,
+	}
+	if latest != nil {
+		vm.subject = latest.Subject
+		for i, t := range schemaTypes {
+			if t == latest.Type {
+				vm.schemaType = i
+			}
+		}
+	}
+	return vm
+}
+
+func (vm *RegisterSchemaViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *RegisterSchemaViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *RegisterSchemaViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *RegisterSchemaViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepRegisterSubject:
+		return "Subject:"
+	case StepRegisterType:
+		return "Schema type (↑↓ to select, Enter to confirm):"
+	case StepRegisterSource:
+		return "Schema source (↑↓ to select, Enter to confirm):"
+	case StepRegisterFile:
+		return "Schema file:"
+	case StepRegisterMode:
+		return "Mode (↑↓ to select, Enter to confirm):"
+	case StepRegisterResult:
+		if vm.canRegisterLocked() {
+			return "Compatibility check (Enter to register, Esc to cancel):"
+		}
+		return "Compatibility check (Enter or Esc to close):"
+	}
+	return ""
+}
+
+// NextStep advances the popup. Leaving the source step opens the editor,
+// leaving the mode step runs the compatibility check.
+func (vm *RegisterSchemaViewModel) NextStep() bool {
+	vm.mu.Lock()
+
+	switch vm.currentStep {
+	case StepRegisterSubject:
+		vm.currentStep = StepRegisterType
+	case StepRegisterType:
+		vm.currentStep = StepRegisterSource
+	case StepRegisterSource:
+		if vm.source == RegisterFromFile {
+			vm.currentStep = StepRegisterFile
+		} else {
+			vm.currentStep = StepRegisterMode
+		}
+	case StepRegisterFile:
+		vm.currentStep = StepRegisterMode
+	case StepRegisterMode:
+		vm.currentStep = StepRegisterResult
+	case StepRegisterResult:
+		done := vm.canRegisterLocked()
+		vm.mu.Unlock()
+		if !done {
+			vm.Cancel()
+		}
+		return done // done, submit
+	}
+
+	step := vm.currentStep
+	fromEditor := vm.source == RegisterFromEditor
+	vm.mu.Unlock()
+
+	switch {
+	case step == StepRegisterMode && fromEditor:
+		vm.editSchema()
+	case step == StepRegisterResult:
+		vm.runCheck()
+	}
+	return false
+}
+
+func (vm *RegisterSchemaViewModel) canRegisterLocked() bool {
+	return !vm.checkOnly && vm.checkErr == nil && vm.result.Compatible
+}
+
+func (vm *RegisterSchemaViewModel) GetTypeOptions() []string {
+	return schemaTypes
+}
+
+func (vm *RegisterSchemaViewModel) GetSelectedTypeIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.schemaType
+}
+
+func (vm *RegisterSchemaViewModel) MoveTypeUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.schemaType = (vm.schemaType + len(schemaTypes) - 1) % len(schemaTypes)
+	vm.notifyChange("type")
+}
+
+func (vm *RegisterSchemaViewModel) MoveTypeDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.schemaType = (vm.schemaType + 1) % len(schemaTypes)
+	vm.notifyChange("type")
+}
+
+func (vm *RegisterSchemaViewModel) GetSourceOptions() []string {
+	return []string{"Write in $EDITOR", "From file"}
+}
+
+func (vm *RegisterSchemaViewModel) GetSelectedSourceIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.source)
+}
+
+// ToggleSource switches between the editor and a file
+func (vm *RegisterSchemaViewModel) ToggleSource() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.source == RegisterFromEditor {
+		vm.source = RegisterFromFile
+	} else {
+		vm.source = RegisterFromEditor
+	}
+	vm.notifyChange("source")
+}
+
+func (vm *RegisterSchemaViewModel) GetModeOptions() []string {
+	return []string{"Check and register", "Check only"}
+}
+
+func (vm *RegisterSchemaViewModel) GetSelectedModeIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.checkOnly {
+		return 1
+	}
+	return 0
+}
+
+// ToggleMode switches between registering and only checking compatibility
+func (vm *RegisterSchemaViewModel) ToggleMode() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.checkOnly = !vm.checkOnly
+	vm.notifyChange("mode")
+}
+
+func (vm *RegisterSchemaViewModel) SetSubject(subject string) {
+	vm.mu.Lock()
+	vm.subject = subject
+	vm.mu.Unlock()
+}
+
+func (vm *RegisterSchemaViewModel) GetSubject() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.subject
+}
+
+func (vm *RegisterSchemaViewModel) SetFilePath(path string) {
+	vm.mu.Lock()
+	vm.filePath = path
+	vm.mu.Unlock()
+}
+
+// editSchema lets the user write the schema in $EDITOR. The file starts with
+// the latest version of the subject when its type matches.
+func (vm *RegisterSchemaViewModel) editSchema() {
+	vm.mu.RLock()
+	schemaType := schemaTypes[vm.schemaType]
+	initial := vm.schema
+	if initial == "" && vm.latest != nil && vm.latest.Subject == vm.subject && vm.latest.Type == schemaType {
+		initial = prettySchema(vm.latest.Schema)
+	}
+	vm.mu.RUnlock()
+
+	schema, err := editTempFile(initial, schemaFileExtension(schemaType))
+
+	vm.mu.Lock()
+	vm.schema = schema
+	vm.checkErr = err
+	vm.mu.Unlock()
+}
+
+func editTempFile(initial, ext string) (string, error) {
+	f, err := os.CreateTemp("", "lazykafka-schema-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(initial)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := openInEditor(f.Name()); err != nil {
+		return "", fmt.Errorf("editor: %w", err)
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func schemaFileExtension(schemaType string) string {
+	switch schemaType {
+	case schemaregistry.SchemaTypeProtobuf:
+		return ".proto"
+	case schemaregistry.SchemaTypeJSON:
+		return ".json"
+	}
+	return ".avsc"
+}
+
+// prettySchema indents JSON schemas, which the registry returns compacted
+func prettySchema(schema string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(schema), "", "  "); err != nil {
+		return schema
+	}
+	return buf.String()
+}
+
+// BuildSchema validates the input and returns the schema to check, reading
+// the file when registering from one
+func (vm *RegisterSchemaViewModel) BuildSchema() (models.SchemaRegistry, error) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	schema := models.SchemaRegistry{
+		Subject: strings.TrimSpace(vm.subject),
+		Type:    schemaTypes[vm.schemaType],
+		Schema:  vm.schema,
+	}
+	if schema.Subject == "" {
+		return schema, errors.Join(ErrValidation, errors.New("subject is required"))
+	}
+
+	if vm.source == RegisterFromFile {
+		path := strings.TrimSpace(vm.filePath)
+		if path == "" {
+			return schema, errors.Join(ErrValidation, errors.New("file path is required"))
+		}
+		data, err := os.ReadFile(models.ExpandHome(path))
+		if err != nil {
+			return schema, err
+		}
+		schema.Schema = string(data)
+	}
+
+	schema.Schema = strings.TrimSpace(schema.Schema)
+	if schema.Schema == "" {
+		return schema, errors.Join(ErrValidation, errors.New("schema is empty"))
+	}
+	if err := schemaregistry.Validate(schema.Type, schema.Schema); err != nil {
+		return schema, errors.Join(ErrValidation, fmt.Errorf("invalid %s schema: %w", schema.Type, err))
+	}
+	return schema, nil
+}
+
+func (vm *RegisterSchemaViewModel) runCheck() {
+	vm.mu.RLock()
+	err := vm.checkErr
+	vm.mu.RUnlock()
+
+	var schema models.SchemaRegistry
+	var result models.CompatibilityResult
+	if err == nil {
+		schema, err = vm.BuildSchema()
+		if err == nil && vm.check != nil {
+			result, err = vm.check(schema)
+		}
+	}
+
+	vm.mu.Lock()
+	vm.checked = schema
+	vm.result = result
+	vm.checkErr = err
+	vm.mu.Unlock()
+	vm.notifyChange("result")
+}
+
+func (vm *RegisterSchemaViewModel) GetResultLineCount() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.checkErr != nil {
+		return 1
+	}
+	return len(vm.result.Messages) + 2
+}
+
+func (vm *RegisterSchemaViewModel) RenderResult() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.checkErr != nil {
+		return fmt.Sprintf(" Error: %s", strings.ReplaceAll(vm.checkErr.Error(), "\n", ": "))
+	}
+
+	var sb strings.Builder
+	switch {
+	case !vm.result.Compatible:
+		sb.WriteString(fmt.Sprintf(" Incompatible with the latest version of %s\n", vm.subject))
+	case vm.checkOnly:
+		sb.WriteString(fmt.Sprintf(" Compatible with the latest version of %s, nothing was registered\n", vm.subject))
+	default:
+		sb.WriteString(fmt.Sprintf(" Compatible with the latest version of %s\n", vm.subject))
+	}
+	sb.WriteString("\n")
+	for _, msg := range vm.result.Messages {
+		sb.WriteString(" - " + msg + "\n")
+	}
+	return sb.String()
+}
+
+// Submit registers the schema that passed the compatibility check
+func (vm *RegisterSchemaViewModel) Submit() error {
+	vm.mu.RLock()
+	if !vm.canRegisterLocked() {
+		vm.mu.RUnlock()
+		return errors.New("schema did not pass the compatibility check")
+	}
+	schema := vm.checked
+	vm.mu.RUnlock()
+
+	if vm.onSubmit != nil {
+		vm.onSubmit(schema)
+	}
+	return nil
+}
+
+func (vm *RegisterSchemaViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
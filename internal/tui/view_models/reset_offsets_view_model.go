@@ -0,0 +1,425 @@
+package viewmodel
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepResetScope     = 0
+	StepResetTopic     = 1
+	StepResetPartition = 2
+	StepResetStrategy  = 3
+	StepResetValue     = 4
+	StepResetPreview   = 5
+)
+
+type OffsetResetPreviewFunc func(spec models.OffsetResetSpec) ([]models.OffsetResetPreview, error)
+
+type ResetOffsetsViewModel struct {
+	mu          sync.RWMutex
+	group       string
+	scope       models.OffsetResetScope
+	topic       string
+	partition   string
+	strategy    models.OffsetResetStrategy
+	value       string
+	previews    []models.OffsetResetPreview
+	previewErr  error
+	currentStep int
+	onChange    types.OnChangeFunc
+	preview     OffsetResetPreviewFunc
+	onSubmit    func(group string, previews []models.OffsetResetPreview)
+	onCancel    func()
+}
+
+func NewResetOffsetsViewModel(
+	group string,
+	defaultTopic string,
+	preview OffsetResetPreviewFunc,
+	onSubmit func(string, []models.OffsetResetPreview),
+	onCancel func(),
+) *ResetOffsetsViewModel {
+	return &ResetOffsetsViewModel{
+		group:       group,
+		scope:       models.ResetScopeGroup,
+		strategy:    models.ResetToEarliest,
+		currentStep: StepResetScope,
+		topic:       defaultTopic,
+		partition:   "0",
+		preview:     preview,
+		onSubmit:    onSubmit,
+		onCancel:    onCancel,
+	}
+}
+
+func (vm *ResetOffsetsViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *ResetOffsetsViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *ResetOffsetsViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *ResetOffsetsViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepResetScope:
+		return fmt.Sprintf("Reset offsets of %s, scope (↑↓ to select, Enter to confirm):", vm.group)
+	case StepResetTopic:
+		return "Topic:"
+	case StepResetPartition:
+		return "Partition:"
+	case StepResetStrategy:
+		return "Reset to (↑↓ to select, Enter to confirm):"
+	case StepResetValue:
+		switch vm.strategy {
+		case models.ResetToOffset:
+			return "Offset:"
+		case models.ResetToTimestamp:
+			return "Timestamp (RFC3339, 2006-01-02 15:04:05 or unix millis):"
+		case models.ResetShiftBy:
+			return "Shift by (e.g. -100 or 50):"
+		}
+	case StepResetPreview:
+		return "Dry run (Enter to apply, Esc to cancel):"
+	}
+	return ""
+}
+
+func (vm *ResetOffsetsViewModel) NextStep() bool {
+	vm.mu.Lock()
+
+	switch vm.currentStep {
+	case StepResetScope:
+		if vm.scope == models.ResetScopeGroup {
+			vm.currentStep = StepResetStrategy
+		} else {
+			vm.currentStep = StepResetTopic
+		}
+	case StepResetTopic:
+		if vm.scope == models.ResetScopePartition {
+			vm.currentStep = StepResetPartition
+		} else {
+			vm.currentStep = StepResetStrategy
+		}
+	case StepResetPartition:
+		vm.currentStep = StepResetStrategy
+	case StepResetStrategy:
+		if vm.needsValue() {
+			vm.currentStep = StepResetValue
+		} else {
+			vm.currentStep = StepResetPreview
+		}
+	case StepResetValue:
+		vm.currentStep = StepResetPreview
+	case StepResetPreview:
+		done := vm.previewErr == nil && len(vm.previews) > 0
+		vm.mu.Unlock()
+		return done // done, submit
+	}
+
+	step := vm.currentStep
+	vm.mu.Unlock()
+
+	if step == StepResetPreview {
+		vm.runPreview()
+	}
+	return false
+}
+
+func (vm *ResetOffsetsViewModel) PrevStep() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	switch vm.currentStep {
+	case StepResetTopic:
+		vm.currentStep = StepResetScope
+	case StepResetPartition:
+		vm.currentStep = StepResetTopic
+	case StepResetStrategy:
+		switch vm.scope {
+		case models.ResetScopePartition:
+			vm.currentStep = StepResetPartition
+		case models.ResetScopeTopic:
+			vm.currentStep = StepResetTopic
+		default:
+			vm.currentStep = StepResetScope
+		}
+	case StepResetValue:
+		vm.currentStep = StepResetStrategy
+	case StepResetPreview:
+		if vm.needsValue() {
+			vm.currentStep = StepResetValue
+		} else {
+			vm.currentStep = StepResetStrategy
+		}
+	}
+}
+
+func (vm *ResetOffsetsViewModel) needsValue() bool {
+	switch vm.strategy {
+	case models.ResetToOffset, models.ResetToTimestamp, models.ResetShiftBy:
+		return true
+	}
+	return false
+}
+
+func (vm *ResetOffsetsViewModel) GetScopeOptions() []string {
+	return []string{"Whole group", "Single topic", "Single partition"}
+}
+
+func (vm *ResetOffsetsViewModel) GetSelectedScopeIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.scope)
+}
+
+func (vm *ResetOffsetsViewModel) MoveScopeUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.scope > 0 {
+		vm.scope--
+	} else {
+		vm.scope = models.ResetScopePartition
+	}
+	vm.notifyChange("scope")
+}
+
+func (vm *ResetOffsetsViewModel) MoveScopeDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.scope < models.ResetScopePartition {
+		vm.scope++
+	} else {
+		vm.scope = models.ResetScopeGroup
+	}
+	vm.notifyChange("scope")
+}
+
+func (vm *ResetOffsetsViewModel) GetStrategyOptions() []string {
+	return []string{"Earliest", "Latest", "Specific offset", "Timestamp", "Shift by N"}
+}
+
+func (vm *ResetOffsetsViewModel) GetSelectedStrategyIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.strategy)
+}
+
+func (vm *ResetOffsetsViewModel) MoveStrategyUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.strategy > 0 {
+		vm.strategy--
+	} else {
+		vm.strategy = models.ResetShiftBy
+	}
+	vm.notifyChange("strategy")
+}
+
+func (vm *ResetOffsetsViewModel) MoveStrategyDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.strategy < models.ResetShiftBy {
+		vm.strategy++
+	} else {
+		vm.strategy = models.ResetToEarliest
+	}
+	vm.notifyChange("strategy")
+}
+
+func (vm *ResetOffsetsViewModel) SetTopic(topic string) {
+	vm.mu.Lock()
+	vm.topic = topic
+	vm.mu.Unlock()
+}
+
+func (vm *ResetOffsetsViewModel) GetTopic() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.topic
+}
+
+func (vm *ResetOffsetsViewModel) SetPartition(p string) {
+	vm.mu.Lock()
+	vm.partition = p
+	vm.mu.Unlock()
+}
+
+func (vm *ResetOffsetsViewModel) GetPartition() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.partition
+}
+
+func (vm *ResetOffsetsViewModel) SetValue(v string) {
+	vm.mu.Lock()
+	vm.value = v
+	vm.mu.Unlock()
+}
+
+func (vm *ResetOffsetsViewModel) GetValue() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.value
+}
+
+func (vm *ResetOffsetsViewModel) BuildSpec() (models.OffsetResetSpec, error) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	spec := models.OffsetResetSpec{
+		Group:    vm.group,
+		Scope:    vm.scope,
+		Strategy: vm.strategy,
+	}
+
+	if vm.scope != models.ResetScopeGroup {
+		spec.Topic = strings.TrimSpace(vm.topic)
+		if spec.Topic == "" {
+			return spec, errors.Join(ErrValidation, errors.New("topic is required"))
+		}
+	}
+
+	if vm.scope == models.ResetScopePartition {
+		partition, err := strconv.Atoi(strings.TrimSpace(vm.partition))
+		if err != nil || partition < 0 {
+			return spec, errors.Join(ErrValidation, errors.New("partition must be a non-negative integer"))
+		}
+		spec.Partition = partition
+	}
+
+	value := strings.TrimSpace(vm.value)
+	switch vm.strategy {
+	case models.ResetToOffset:
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || offset < 0 {
+			return spec, errors.Join(ErrValidation, errors.New("offset must be a non-negative integer"))
+		}
+		spec.Offset = offset
+	case models.ResetToTimestamp:
+		ts, err := models.ParseTimestamp(value)
+		if err != nil {
+			return spec, errors.Join(ErrValidation, err)
+		}
+		spec.Timestamp = ts
+	case models.ResetShiftBy:
+		shift, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return spec, errors.Join(ErrValidation, errors.New("shift must be an integer"))
+		}
+		spec.Shift = shift
+	}
+
+	return spec, nil
+}
+
+func (vm *ResetOffsetsViewModel) runPreview() {
+	spec, err := vm.BuildSpec()
+
+	var previews []models.OffsetResetPreview
+	if err == nil && vm.preview != nil {
+		previews, err = vm.preview(spec)
+	}
+
+	vm.mu.Lock()
+	vm.previews = previews
+	vm.previewErr = err
+	vm.mu.Unlock()
+	vm.notifyChange("previews")
+}
+
+func (vm *ResetOffsetsViewModel) GetPreviewLineCount() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.previewErr != nil || len(vm.previews) == 0 {
+		return 1
+	}
+	return len(vm.previews) + 2
+}
+
+func (vm *ResetOffsetsViewModel) RenderPreview() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.previewErr != nil {
+		return fmt.Sprintf(" Error: %s", strings.ReplaceAll(vm.previewErr.Error(), "\n", ": "))
+	}
+	if len(vm.previews) == 0 {
+		return " Nothing to reset"
+	}
+
+	var sb strings.Builder
+
+	headers := []string{"Topic", "Partition", "Current", "New", "Change"}
+	colWidths := []int{30, 12, 14, 14, 14}
+
+	sb.WriteString(" ")
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n ")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for _, p := range vm.previews {
+		current := "-"
+		change := "-"
+		if p.OldOffset >= 0 {
+			current = strconv.FormatInt(p.OldOffset, 10)
+			change = fmt.Sprintf("%+d", p.NewOffset-p.OldOffset)
+		}
+		sb.WriteString(fmt.Sprintf(" %-*s%-*d%-*s%-*d%-*s\n",
+			colWidths[0], p.Topic,
+			colWidths[1], p.Partition,
+			colWidths[2], current,
+			colWidths[3], p.NewOffset,
+			colWidths[4], change,
+		))
+	}
+
+	return sb.String()
+}
+
+func (vm *ResetOffsetsViewModel) Submit() error {
+	vm.mu.RLock()
+	if vm.previewErr != nil {
+		err := vm.previewErr
+		vm.mu.RUnlock()
+		return err
+	}
+	group := vm.group
+	previews := vm.previews
+	vm.mu.RUnlock()
+
+	if vm.onSubmit != nil {
+		vm.onSubmit(group, previews)
+	}
+	return nil
+}
+
+func (vm *ResetOffsetsViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
@@ -0,0 +1,43 @@
+package viewmodel
+
+// ringBuffer keeps the most recent items up to a fixed capacity, overwriting
+// the oldest ones once full
+type ringBuffer[T any] struct {
+	items []T
+	start int
+	size  int
+}
+
+func newRingBuffer[T any](capacity int) *ringBuffer[T] {
+	return &ringBuffer[T]{items: make([]T, capacity)}
+}
+
+func (r *ringBuffer[T]) Push(items ...T) {
+	for _, item := range items {
+		end := (r.start + r.size) % len(r.items)
+		r.items[end] = item
+		if r.size < len(r.items) {
+			r.size++
+		} else {
+			r.start = (r.start + 1) % len(r.items)
+		}
+	}
+}
+
+func (r *ringBuffer[T]) Len() int {
+	return r.size
+}
+
+func (r *ringBuffer[T]) Cap() int {
+	return len(r.items)
+}
+
+// Last returns up to n of the most recent items, oldest first
+func (r *ringBuffer[T]) Last(n int) []T {
+	n = min(max(n, 0), r.size)
+	out := make([]T, n)
+	for i := range n {
+		out[i] = r.items[(r.start+r.size-n+i)%len(r.items)]
+	}
+	return out
+}
@@ -1,26 +1,72 @@
 package viewmodel
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
 
 	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/schemaregistry"
 	"github.com/jurabek/lazykafka/internal/tui/types"
 )
 
+type SchemaTabType int
+
+const (
+	SchemaTabDefinition SchemaTabType = iota
+	SchemaTabVersions
+	SchemaTabDiff
+)
+
+var schemaTabNames = []string{"Schema", "Versions", "Diff"}
+
+// SchemaRegistryDetailViewModel shows one version of the selected subject at
+// a time. Versions are fetched when first viewed and kept until another
+// subject is selected. The diff compares the viewed version against the
+// marked base version, or against the version before it.
 type SchemaRegistryDetailViewModel struct {
 	mu              sync.RWMutex
 	schema          *models.SchemaRegistry
+	subjectLevel    string
+	globalLevel     string
+	versions        []int
+	cache           map[int]models.SchemaRegistry
+	fetching        map[int]bool
+	selected        int
+	base            int
+	activeTab       SchemaTabType
 	onChange        types.OnChangeFunc
 	commandBindings []*types.CommandBinding
+	registryClient  schemaregistry.Client
+	onError         func(err error)
 }
 
 func NewSchemaRegistryDetailViewModel() *SchemaRegistryDetailViewModel {
-	vm := &SchemaRegistryDetailViewModel{}
-	vm.commandBindings = []*types.CommandBinding{}
+	vm := &SchemaRegistryDetailViewModel{
+		activeTab: SchemaTabDefinition,
+	}
+	vm.initCommandBindings()
 	return vm
 }
 
+func (vm *SchemaRegistryDetailViewModel) initCommandBindings() {
+	prevTab := types.NewCommand(vm.PrevTab)
+	nextTab := types.NewCommand(vm.NextTab)
+	prevVersion := types.NewCommand(vm.PrevVersion)
+	nextVersion := types.NewCommand(vm.NextVersion)
+	markBase := types.NewCommand(vm.MarkBase)
+
+	vm.commandBindings = []*types.CommandBinding{
+		{Key: '[', Cmd: prevTab},
+		{Key: ']', Cmd: nextTab},
+		{Key: '<', Cmd: prevVersion},
+		{Key: '>', Cmd: nextVersion},
+		{Key: 'b', Cmd: markBase},
+	}
+}
+
 func (vm *SchemaRegistryDetailViewModel) SetOnChange(fn types.OnChangeFunc) {
 	vm.onChange = fn
 }
@@ -53,7 +99,7 @@ func (vm *SchemaRegistryDetailViewModel) GetTitle() string {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
 	if vm.schema != nil {
-		return fmt.Sprintf("%s (v%d)", vm.schema.Subject, vm.schema.Version)
+		return fmt.Sprintf("%s (v%d)", vm.schema.Subject, vm.selected)
 	}
 	return "Schema"
 }
@@ -62,11 +108,137 @@ func (vm *SchemaRegistryDetailViewModel) GetName() string {
 	return "schema_registry_detail"
 }
 
+func (vm *SchemaRegistryDetailViewModel) SetSchemaRegistryClient(client schemaregistry.Client) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.registryClient = client
+}
+
+func (vm *SchemaRegistryDetailViewModel) SetOnError(fn func(err error)) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.onError = fn
+}
+
+// SetSchema shows schema, the latest version of its subject, and loads the
+// list of all versions of the subject
 func (vm *SchemaRegistryDetailViewModel) SetSchema(schema *models.SchemaRegistry) {
 	vm.mu.Lock()
 	vm.schema = schema
+	vm.versions = nil
+	vm.cache = make(map[int]models.SchemaRegistry)
+	vm.fetching = make(map[int]bool)
+	vm.selected = 0
+	vm.base = 0
+	vm.subjectLevel = ""
+	vm.globalLevel = ""
+	if schema != nil {
+		vm.cache[schema.Version] = *schema
+		vm.selected = schema.Version
+		vm.versions = []int{schema.Version}
+	}
+	client := vm.registryClient
+	onError := vm.onError
 	vm.mu.Unlock()
 	vm.notifyChange(types.FieldItems)
+
+	if schema == nil || client == nil {
+		return
+	}
+
+	vm.loadCompatibility()
+	go func() {
+		versions, err := client.ListVersions(context.Background(), schema.Subject)
+		if err != nil {
+			slog.Error("failed to load schema versions", slog.String("subject", schema.Subject), slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+
+		vm.mu.Lock()
+		if vm.schema != schema {
+			vm.mu.Unlock()
+			return
+		}
+		if len(versions) > 0 {
+			vm.versions = versions
+		}
+		vm.mu.Unlock()
+		vm.notifyChange(types.FieldItems)
+		vm.fetchVisible()
+	}()
+}
+
+// loadCompatibility fetches the level of the subject and the global level
+func (vm *SchemaRegistryDetailViewModel) loadCompatibility() {
+	vm.mu.RLock()
+	schema := vm.schema
+	client := vm.registryClient
+	onError := vm.onError
+	vm.mu.RUnlock()
+
+	if schema == nil || client == nil {
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		subjectLevel, err := client.GetCompatibility(ctx, schema.Subject)
+		var globalLevel string
+		if err == nil {
+			globalLevel, err = client.GetCompatibility(ctx, "")
+		}
+		if err != nil {
+			slog.Error("failed to load compatibility level", slog.String("subject", schema.Subject), slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+
+		vm.mu.Lock()
+		if vm.schema != schema {
+			vm.mu.Unlock()
+			return
+		}
+		vm.subjectLevel = subjectLevel
+		vm.globalLevel = globalLevel
+		vm.mu.Unlock()
+		vm.notifyChange(types.FieldItems)
+	}()
+}
+
+// SetCompatibility changes the level of subject, or the global level when
+// subject is empty. An empty level makes subject use the global level.
+func (vm *SchemaRegistryDetailViewModel) SetCompatibility(ctx context.Context, subject, level string) error {
+	vm.mu.RLock()
+	client := vm.registryClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no schema registry configured")
+	}
+
+	var err error
+	if level == "" {
+		err = client.ResetCompatibility(ctx, subject)
+	} else {
+		err = client.SetCompatibility(ctx, subject, level)
+	}
+	if err != nil {
+		return err
+	}
+	vm.loadCompatibility()
+	return nil
+}
+
+// GetSelectedVersion returns the version being viewed, 0 without a schema
+func (vm *SchemaRegistryDetailViewModel) GetSelectedVersion() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.selected
 }
 
 func (vm *SchemaRegistryDetailViewModel) GetSchema() *models.SchemaRegistry {
@@ -75,7 +247,188 @@ func (vm *SchemaRegistryDetailViewModel) GetSchema() *models.SchemaRegistry {
 	return vm.schema
 }
 
-func (vm *SchemaRegistryDetailViewModel) GetSchemaContent() string {
+// SelectedSchema returns the version being viewed, if it was fetched
+func (vm *SchemaRegistryDetailViewModel) SelectedSchema() (models.SchemaRegistry, bool) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	schema, ok := vm.cache[vm.selected]
+	return schema, ok
+}
+
+// fetchVisible fetches the viewed version and the version it is diffed
+// against if they are not cached yet
+func (vm *SchemaRegistryDetailViewModel) fetchVisible() {
+	vm.mu.Lock()
+	schema := vm.schema
+	client := vm.registryClient
+	onError := vm.onError
+	var missing []int
+	for _, v := range []int{vm.selected, vm.baseVersion()} {
+		if _, ok := vm.cache[v]; v > 0 && !ok && !vm.fetching[v] {
+			vm.fetching[v] = true
+			missing = append(missing, v)
+		}
+	}
+	vm.mu.Unlock()
+
+	if schema == nil || client == nil {
+		return
+	}
+
+	for _, version := range missing {
+		go func() {
+			fetched, err := client.GetSchema(context.Background(), schema.Subject, version)
+
+			vm.mu.Lock()
+			if vm.schema != schema {
+				vm.mu.Unlock()
+				return
+			}
+			delete(vm.fetching, version)
+			if err == nil {
+				vm.cache[version] = fetched
+			}
+			vm.mu.Unlock()
+
+			if err != nil {
+				slog.Error("failed to load schema version",
+					slog.String("subject", schema.Subject), slog.Int("version", version), slog.Any("error", err))
+				if onError != nil {
+					onError(err)
+				}
+				return
+			}
+			vm.notifyChange(types.FieldItems)
+		}()
+	}
+}
+
+// baseVersion returns the version the viewed one is diffed against, 0 if
+// there is none. Callers hold the lock.
+func (vm *SchemaRegistryDetailViewModel) baseVersion() int {
+	if vm.base > 0 && vm.base != vm.selected {
+		return vm.base
+	}
+	for i, v := range vm.versions {
+		if v == vm.selected && i > 0 {
+			return vm.versions[i-1]
+		}
+	}
+	return 0
+}
+
+func (vm *SchemaRegistryDetailViewModel) PrevVersion() error {
+	return vm.moveVersion(-1)
+}
+
+func (vm *SchemaRegistryDetailViewModel) NextVersion() error {
+	return vm.moveVersion(1)
+}
+
+func (vm *SchemaRegistryDetailViewModel) moveVersion(delta int) error {
+	vm.mu.Lock()
+	moved := false
+	for i, v := range vm.versions {
+		if v == vm.selected && i+delta >= 0 && i+delta < len(vm.versions) {
+			vm.selected = vm.versions[i+delta]
+			moved = true
+			break
+		}
+	}
+	vm.mu.Unlock()
+
+	if !moved {
+		return types.ErrNoSelection
+	}
+	vm.notifyChange(types.FieldItems)
+	vm.fetchVisible()
+	return nil
+}
+
+// MarkBase makes the viewed version the base of the diff. Marking the base
+// again goes back to diffing against the previous version.
+func (vm *SchemaRegistryDetailViewModel) MarkBase() error {
+	vm.mu.Lock()
+	if vm.schema == nil {
+		vm.mu.Unlock()
+		return nil
+	}
+	if vm.base == vm.selected {
+		vm.base = 0
+	} else {
+		vm.base = vm.selected
+	}
+	vm.mu.Unlock()
+
+	vm.notifyChange(types.FieldItems)
+	vm.fetchVisible()
+	return nil
+}
+
+func (vm *SchemaRegistryDetailViewModel) GetActiveTab() SchemaTabType {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.activeTab
+}
+
+func (vm *SchemaRegistryDetailViewModel) SetActiveTab(tab SchemaTabType) {
+	vm.mu.Lock()
+	vm.activeTab = tab
+	vm.mu.Unlock()
+	vm.notifyChange(types.FieldSelectedIndex)
+}
+
+func (vm *SchemaRegistryDetailViewModel) NextTab() error {
+	vm.SetActiveTab((vm.GetActiveTab() + 1) % SchemaTabType(len(schemaTabNames)))
+	return nil
+}
+
+func (vm *SchemaRegistryDetailViewModel) PrevTab() error {
+	tab := vm.GetActiveTab() - 1
+	if tab < 0 {
+		tab = SchemaTabType(len(schemaTabNames) - 1)
+	}
+	vm.SetActiveTab(tab)
+	return nil
+}
+
+func (vm *SchemaRegistryDetailViewModel) RenderTabs() string {
+	return formatTabs(schemaTabNames, int(vm.GetActiveTab()))
+}
+
+func (vm *SchemaRegistryDetailViewModel) RenderSchema() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.schema == nil {
+		return "  Select a schema to view details"
+	}
+	schema, ok := vm.cache[vm.selected]
+	if !ok {
+		return fmt.Sprintf("  Loading version %d...", vm.selected)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Type: %s  ID: %d  Version: %d (latest %d)\n",
+		schema.Type, schema.ID, schema.Version, vm.versions[len(vm.versions)-1]))
+	sb.WriteString(fmt.Sprintf("Compatibility: %s\n\n", vm.formatCompatibility()))
+	sb.WriteString(schema.Schema)
+	return sb.String()
+}
+
+// formatCompatibility describes the level that applies to the subject and
+// where it comes from. Callers hold the lock.
+func (vm *SchemaRegistryDetailViewModel) formatCompatibility() string {
+	switch {
+	case vm.globalLevel == "":
+		return "loading..."
+	case vm.subjectLevel == "":
+		return vm.globalLevel + " (global)"
+	}
+	return fmt.Sprintf("%s (subject, global %s)", vm.subjectLevel, vm.globalLevel)
+}
+
+func (vm *SchemaRegistryDetailViewModel) RenderVersionsTable(width int) string {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
 
@@ -83,15 +436,104 @@ func (vm *SchemaRegistryDetailViewModel) GetSchemaContent() string {
 		return "  Select a schema to view details"
 	}
 
-	return vm.schema.Schema
+	var sb strings.Builder
+
+	headers := []string{"", "Version", "ID", "Type"}
+	colWidths := []int{4, 10, 10, 12}
+
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	base := vm.baseVersion()
+	for i := len(vm.versions) - 1; i >= 0; i-- {
+		v := vm.versions[i]
+		marker := ""
+		switch v {
+		case vm.selected:
+			marker = ">"
+		case base:
+			marker = "b"
+		}
+		id, schemaType := "-", "-"
+		if schema, ok := vm.cache[v]; ok {
+			id = fmt.Sprintf("%d", schema.ID)
+			schemaType = schema.Type
+		}
+		sb.WriteString(fmt.Sprintf("%-*s%-*d%-*s%s\n",
+			colWidths[0], marker,
+			colWidths[1], v,
+			colWidths[2], id,
+			schemaType,
+		))
+	}
+	sb.WriteString("\n  > viewed  b: diff base\n")
+
+	return sb.String()
 }
 
-func (vm *SchemaRegistryDetailViewModel) GetSchemaType() string {
+func (vm *SchemaRegistryDetailViewModel) RenderDiff(width int) string {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
 
 	if vm.schema == nil {
-		return ""
+		return "  Select a schema to view details"
+	}
+	base := vm.baseVersion()
+	if base == 0 {
+		return fmt.Sprintf("  Version %d is the first version, mark another one with b to compare", vm.selected)
+	}
+	oldSchema, okOld := vm.cache[base]
+	newSchema, okNew := vm.cache[vm.selected]
+	if !okOld || !okNew {
+		return "  Loading versions..."
+	}
+	if oldSchema.Type != newSchema.Type {
+		return fmt.Sprintf("  Schema type changed from %s to %s", oldSchema.Type, newSchema.Type)
+	}
+
+	changes, err := schemaregistry.Diff(newSchema.Type, oldSchema.Schema, newSchema.Schema)
+	if err != nil {
+		return "  " + err.Error()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("v%d -> v%d: %d change(s)\n\n", base, vm.selected, len(changes)))
+	if len(changes) == 0 {
+		sb.WriteString("  No structural changes\n")
+		return sb.String()
+	}
+
+	pathWidth := 0
+	for _, c := range changes {
+		pathWidth = max(pathWidth, len(c.Path))
+	}
+	pathWidth = min(pathWidth+2, 48)
+
+	for _, c := range changes {
+		var detail string
+		switch c.Kind {
+		case models.SchemaFieldAdded:
+			detail = c.New
+		case models.SchemaFieldRemoved:
+			detail = c.Old
+		case models.SchemaFieldRenamed:
+			detail = "renamed to " + c.RenamedTo
+			if c.Old != c.New {
+				detail += ", " + c.Old + " -> " + c.New
+			}
+		default:
+			detail = c.Old + " -> " + c.New
+		}
+		sb.WriteString(fmt.Sprintf("%s %-*s%s\n", c.Kind.Symbol(), pathWidth, c.Path, detail))
 	}
-	return vm.schema.Type
+
+	return sb.String()
 }
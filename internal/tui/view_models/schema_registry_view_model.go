@@ -1,11 +1,15 @@
 package viewmodel
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"sort"
 	"sync"
 
 	"github.com/jroimartin/gocui"
 	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/schemaregistry"
 	"github.com/jurabek/lazykafka/internal/tui/types"
 )
 
@@ -18,8 +22,17 @@ type SchemaRegistryViewModel struct {
 	onChange           types.OnChangeFunc
 	commandBindings    []*types.CommandBinding
 	onSelectionChanged SRSelectionChangedFunc
+	registryClient     schemaregistry.Client
+	// loadGen identifies the latest load, so a slow one finishing late does
+	// not replace the subjects of another registry
+	loadGen int
+	onError func(err error)
 }
 
+// schemaFetchWorkers bounds the concurrent requests for the latest version of
+// each subject
+const schemaFetchWorkers = 8
+
 func NewSchemaRegistryViewModel() *SchemaRegistryViewModel {
 	vm := &SchemaRegistryViewModel{
 		selectedIndex: -1,
@@ -27,12 +40,14 @@ func NewSchemaRegistryViewModel() *SchemaRegistryViewModel {
 
 	moveUp := types.NewCommand(vm.MoveUp)
 	moveDown := types.NewCommand(vm.MoveDown)
+	refresh := types.NewCommand(vm.Refresh)
 
 	vm.commandBindings = []*types.CommandBinding{
 		{Key: 'k', Cmd: moveUp},
 		{Key: 'j', Cmd: moveDown},
 		{Key: gocui.KeyArrowUp, Cmd: moveUp},
 		{Key: gocui.KeyArrowDown, Cmd: moveDown},
+		{Key: 'r', Cmd: refresh},
 	}
 
 	return vm
@@ -148,17 +163,208 @@ func (vm *SchemaRegistryViewModel) GetSelectedSchemaRegistry() *models.SchemaReg
 
 func (vm *SchemaRegistryViewModel) Load(schemaRegistries []models.SchemaRegistry) {
 	vm.mu.Lock()
+	selected := 0
+	if vm.selectedIndex >= 0 && vm.selectedIndex < len(vm.schemaRegistries) {
+		// keep the selection on the same subject across reloads
+		subject := vm.schemaRegistries[vm.selectedIndex].Subject
+		for i, sr := range schemaRegistries {
+			if sr.Subject == subject {
+				selected = i
+				break
+			}
+		}
+	}
 	vm.schemaRegistries = schemaRegistries
 	vm.selectedIndex = -1
 	vm.mu.Unlock()
 
 	vm.notifyChange(types.FieldItems)
-	vm.SetSelectedIndex(0)
+	if len(schemaRegistries) == 0 {
+		// nothing to select, clear whatever the detail panel still shows
+		vm.mu.RLock()
+		callback := vm.onSelectionChanged
+		vm.mu.RUnlock()
+		if callback != nil {
+			callback(nil)
+		}
+		return
+	}
+	vm.SetSelectedIndex(selected)
+}
+
+// SetSchemaRegistryClient sets the registry of the selected broker, nil when
+// the broker has none configured
+func (vm *SchemaRegistryViewModel) SetSchemaRegistryClient(client schemaregistry.Client) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.registryClient = client
+	vm.loadGen++
+}
+
+func (vm *SchemaRegistryViewModel) SetOnError(fn func(err error)) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.onError = fn
+}
+
+// HasSchemaRegistry reports whether the selected broker has a registry
+func (vm *SchemaRegistryViewModel) HasSchemaRegistry() bool {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.registryClient != nil
+}
+
+// CheckCompatibility tests schema against the latest version of its subject
+func (vm *SchemaRegistryViewModel) CheckCompatibility(ctx context.Context, schema models.SchemaRegistry) (models.CompatibilityResult, error) {
+	vm.mu.RLock()
+	client := vm.registryClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return models.CompatibilityResult{}, fmt.Errorf("no schema registry configured")
+	}
+	return client.CheckCompatibility(ctx, schema)
+}
+
+// RegisterSchema registers schema as a new version of its subject and
+// reloads the subjects
+func (vm *SchemaRegistryViewModel) RegisterSchema(ctx context.Context, schema models.SchemaRegistry) (int, error) {
+	vm.mu.RLock()
+	client := vm.registryClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return 0, fmt.Errorf("no schema registry configured")
+	}
+	id, err := client.RegisterSchema(ctx, schema)
+	if err != nil {
+		return 0, err
+	}
+	vm.loadSchemasAsync()
+	return id, nil
+}
+
+// DeleteSubject deletes subject, or only one of its versions when version is
+// positive, and reloads the subjects
+func (vm *SchemaRegistryViewModel) DeleteSubject(ctx context.Context, subject string, version int, permanent bool) error {
+	vm.mu.RLock()
+	client := vm.registryClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no schema registry configured")
+	}
+
+	var err error
+	if version > 0 {
+		err = client.DeleteVersion(ctx, subject, version, permanent)
+	} else {
+		err = client.DeleteSubject(ctx, subject, permanent)
+	}
+	if err != nil {
+		return err
+	}
+	vm.loadSchemasAsync()
+	return nil
+}
+
+func (vm *SchemaRegistryViewModel) LoadForBroker(_ *models.Broker) {
+	vm.loadSchemasAsync()
 }
 
-func (vm *SchemaRegistryViewModel) LoadForBroker(broker *models.Broker) {
+// Refresh reloads the subjects of the registry
+func (vm *SchemaRegistryViewModel) Refresh() error {
+	vm.loadSchemasAsync()
+	return nil
+}
+
+func (vm *SchemaRegistryViewModel) loadSchemasAsync() {
+	vm.mu.Lock()
+	client := vm.registryClient
+	onError := vm.onError
+	vm.loadGen++
+	gen := vm.loadGen
+	vm.mu.Unlock()
+
+	if client == nil {
+		vm.Load(nil)
+		return
+	}
+
 	go func() {
-		schemaRegistries := models.MockSchemaRegistries()
-		vm.Load(schemaRegistries)
+		schemas, skipped, err := loadLatestSchemas(context.Background(), client)
+
+		vm.mu.RLock()
+		stale := gen != vm.loadGen
+		vm.mu.RUnlock()
+		if stale {
+			return
+		}
+
+		if err != nil {
+			slog.Error("failed to load schemas", slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+		vm.Load(schemas)
+		if skipped > 0 && onError != nil {
+			onError(fmt.Errorf("%d subjects could not be loaded and are not listed", skipped))
+		}
 	}()
 }
+
+// loadLatestSchemas fetches the latest version of every subject. Subjects
+// deleted between listing and fetching are left out, as are those failing to
+// load, which are only counted so one unreadable subject does not hide the
+// others.
+func loadLatestSchemas(ctx context.Context, client schemaregistry.Client) ([]models.SchemaRegistry, int, error) {
+	subjects, err := client.ListSubjects(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Strings(subjects)
+
+	schemas := make([]models.SchemaRegistry, len(subjects))
+	found := make([]bool, len(subjects))
+	failed := make([]bool, len(subjects))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(schemaFetchWorkers, len(subjects)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				schema, err := client.GetLatestSchema(ctx, subjects[i])
+				if schemaregistry.IsNotFound(err) {
+					continue
+				}
+				if err != nil {
+					slog.Warn("failed to load schema", slog.String("subject", subjects[i]), slog.Any("error", err))
+					failed[i] = true
+					continue
+				}
+				schemas[i], found[i] = schema, true
+			}
+		}()
+	}
+	for i := range subjects {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	result := make([]models.SchemaRegistry, 0, len(subjects))
+	skipped := 0
+	for i := range subjects {
+		if failed[i] {
+			skipped++
+		}
+		if found[i] {
+			result = append(result, schemas[i])
+		}
+	}
+	return result, skipped, nil
+}
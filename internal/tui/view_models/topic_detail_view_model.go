@@ -2,13 +2,18 @@ package viewmodel
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/jurabek/lazykafka/internal/kafka"
 	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/schemaregistry"
 	"github.com/jurabek/lazykafka/internal/tui/types"
 )
 
@@ -17,16 +22,51 @@ type TabType int
 const (
 	TabPartitions TabType = iota
 	TabConfiguration
+	TabMessages
+)
+
+func (t TabType) String() string {
+	switch t {
+	case TabConfiguration:
+		return "Configuration"
+	case TabMessages:
+		return "Messages"
+	default:
+		return "Partitions"
+	}
+}
+
+var topicTabs = []TabType{TabPartitions, TabConfiguration, TabMessages}
+
+const (
+	messagePageSize = 20
+	messagesTimeout = 15 * time.Second
+	// tailBufferSize bounds the records kept in memory while following a topic
+	tailBufferSize = 500
 )
 
 type TopicDetailViewModel struct {
 	mu              sync.RWMutex
 	topic           *models.Topic
 	partitions      []models.Partition
+	configs         []models.ConfigEntry
+	messages        []models.Message
+	messageQuery    *models.MessageQuery
+	pageHistory     []models.MessageQuery
+	nextOffsets     map[int]int64
+	messagesLoading bool
+	messagesGen     int
+	streamCtx       context.Context
+	tail            *ringBuffer[models.Message]
+	tailReceived    int
+	tailCancel      context.CancelFunc
+	tailPaused      bool
+	tailResume      chan struct{}
 	activeTab       TabType
 	onChange        types.OnChangeFunc
 	commandBindings []*types.CommandBinding
 	kafkaClient     kafka.KafkaClient
+	deserializer    *schemaregistry.Deserializer
 	onError         func(err error)
 }
 
@@ -39,7 +79,21 @@ func NewTopicDetailViewModel() *TopicDetailViewModel {
 }
 
 func (vm *TopicDetailViewModel) initCommandBindings() {
-	vm.commandBindings = []*types.CommandBinding{}
+	prevTab := types.NewCommand(vm.PrevTab)
+	nextTab := types.NewCommand(vm.NextTab)
+	prevPage := types.NewCommand(vm.PrevPage)
+	nextPage := types.NewCommand(vm.NextPage)
+	toggleFollow := types.NewCommand(vm.ToggleFollow)
+	togglePause := types.NewCommand(vm.TogglePause)
+
+	vm.commandBindings = []*types.CommandBinding{
+		{Key: '[', Cmd: prevTab},
+		{Key: ']', Cmd: nextTab},
+		{Key: '<', Cmd: prevPage},
+		{Key: '>', Cmd: nextPage},
+		{Key: 'f', Cmd: toggleFollow},
+		{Key: 'p', Cmd: togglePause},
+	}
 }
 
 func (vm *TopicDetailViewModel) SetOnChange(fn types.OnChangeFunc) {
@@ -91,19 +145,52 @@ func (vm *TopicDetailViewModel) SetKafkaClient(client kafka.KafkaClient) {
 	vm.kafkaClient = client
 }
 
+// SetSchemaRegistryClient sets the registry keys and values in the wire format
+// are decoded with, nil leaves them undecoded. Cached schemas are dropped.
+func (vm *TopicDetailViewModel) SetSchemaRegistryClient(client schemaregistry.Client) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.deserializer = nil
+	if client != nil {
+		vm.deserializer = schemaregistry.NewDeserializer(client)
+	}
+}
+
 func (vm *TopicDetailViewModel) SetOnError(fn func(err error)) {
 	vm.mu.Lock()
 	defer vm.mu.Unlock()
 	vm.onError = fn
 }
 
+// SetStreamContext sets the context long-running streams such as follow mode
+// are bound to. It is cancelled when the topic or broker selection changes.
+func (vm *TopicDetailViewModel) SetStreamContext(ctx context.Context) {
+	vm.mu.Lock()
+	vm.streamCtx = ctx
+	following := vm.tail != nil
+	vm.stopTailLocked()
+	vm.mu.Unlock()
+
+	if following {
+		vm.notifyChange(types.FieldItems)
+	}
+}
+
 func (vm *TopicDetailViewModel) SetTopic(topic *models.Topic) {
 	vm.mu.Lock()
 	vm.topic = topic
-	client := vm.kafkaClient
-	onError := vm.onError
+	vm.configs = nil
+	if topic != nil {
+		vm.configs = topic.Configs
+	}
+	vm.resetMessagesLocked()
+	browsing := vm.activeTab == TabMessages
 	vm.mu.Unlock()
 
+	if topic != nil && browsing {
+		vm.BrowseMessages(vm.defaultMessageQuery(topic.Name))
+	}
+
 	if topic == nil {
 		vm.mu.Lock()
 		vm.partitions = nil
@@ -112,6 +199,33 @@ func (vm *TopicDetailViewModel) SetTopic(topic *models.Topic) {
 		return
 	}
 
+	vm.loadPartitions(topic)
+}
+
+// Refresh reloads the partitions and the current message page of the
+// selected topic, leaving follow mode untouched
+func (vm *TopicDetailViewModel) Refresh() {
+	vm.mu.RLock()
+	topic := vm.topic
+	query := vm.messageQuery
+	vm.mu.RUnlock()
+
+	if topic == nil {
+		return
+	}
+	if query != nil {
+		vm.loadMessages(*query)
+	}
+	vm.loadPartitions(topic)
+	vm.loadConfigs(topic)
+}
+
+func (vm *TopicDetailViewModel) loadPartitions(topic *models.Topic) {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	onError := vm.onError
+	vm.mu.RUnlock()
+
 	if client == nil {
 		vm.notifyChange(types.FieldItems)
 		return
@@ -134,6 +248,129 @@ func (vm *TopicDetailViewModel) SetTopic(topic *models.Topic) {
 	}()
 }
 
+func (vm *TopicDetailViewModel) loadConfigs(topic *models.Topic) {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	onError := vm.onError
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return
+	}
+
+	go func() {
+		configs, err := client.GetTopicConfigs(context.Background(), topic.Name)
+		if err != nil {
+			slog.Error("failed to load topic configs", slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+
+		vm.mu.Lock()
+		if vm.topic == nil || vm.topic.Name != topic.Name {
+			vm.mu.Unlock()
+			return
+		}
+		vm.configs = configs
+		vm.mu.Unlock()
+		vm.notifyChange(types.FieldItems)
+	}()
+}
+
+func (vm *TopicDetailViewModel) ValidateConfig(ctx context.Context, topic string, alteration models.ConfigAlteration) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+	return client.ValidateTopicConfigs(ctx, topic, []models.ConfigAlteration{alteration})
+}
+
+func (vm *TopicDetailViewModel) AlterConfig(ctx context.Context, topic string, alteration models.ConfigAlteration) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	current := vm.topic
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+	slog.Info("alter topic config", slog.String("topic", topic), slog.Any("alteration", alteration))
+
+	if err := client.AlterTopicConfigs(ctx, topic, []models.ConfigAlteration{alteration}); err != nil {
+		return err
+	}
+
+	if current != nil && current.Name == topic {
+		vm.loadConfigs(current)
+	}
+	return nil
+}
+
+func (vm *TopicDetailViewModel) PreviewTruncate(ctx context.Context, spec models.TruncateSpec) ([]models.TruncatePreview, error) {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("no active kafka client")
+	}
+	return client.PreviewTruncate(ctx, spec)
+}
+
+// DeleteRecords truncates the previewed partitions and reloads the start
+// offsets shown in the partitions table
+func (vm *TopicDetailViewModel) DeleteRecords(ctx context.Context, previews []models.TruncatePreview) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+
+	err := client.DeleteRecords(ctx, previews)
+	vm.Refresh()
+	return err
+}
+
+// HasKeyedRecords reports whether topic is compacted or any of its latest
+// records carries a key
+func (vm *TopicDetailViewModel) HasKeyedRecords(ctx context.Context, topic *models.Topic) (bool, error) {
+	if strings.Contains(strings.ToLower(topic.CleanUpPolicy), "compact") {
+		return true, nil
+	}
+
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return false, fmt.Errorf("no active kafka client")
+	}
+
+	page, err := client.FetchMessages(ctx, vm.defaultMessageQuery(topic.Name))
+	if err != nil {
+		return false, err
+	}
+	for _, m := range page.Messages {
+		if m.Key != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (vm *TopicDetailViewModel) GetConfigs() []models.ConfigEntry {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.configs
+}
+
 func (vm *TopicDetailViewModel) GetTopic() *models.Topic {
 	vm.mu.RLock()
 	defer vm.mu.RUnlock()
@@ -155,8 +392,531 @@ func (vm *TopicDetailViewModel) GetActiveTab() TabType {
 func (vm *TopicDetailViewModel) SetActiveTab(tab TabType) {
 	vm.mu.Lock()
 	vm.activeTab = tab
+	loadMessages := tab == TabMessages && vm.topic != nil && vm.messageQuery == nil && vm.tail == nil
+	topic := vm.topic
 	vm.mu.Unlock()
 	vm.notifyChange(types.FieldSelectedIndex)
+
+	if loadMessages {
+		vm.BrowseMessages(vm.defaultMessageQuery(topic.Name))
+	}
+	if tab == TabConfiguration && topic != nil {
+		vm.loadConfigs(topic)
+	}
+}
+
+func (vm *TopicDetailViewModel) NextTab() error {
+	idx := tabIndex(topicTabs, vm.GetActiveTab())
+	vm.SetActiveTab(topicTabs[(idx+1)%len(topicTabs)])
+	return nil
+}
+
+func (vm *TopicDetailViewModel) PrevTab() error {
+	idx := tabIndex(topicTabs, vm.GetActiveTab()) - 1
+	if idx < 0 {
+		idx = len(topicTabs) - 1
+	}
+	vm.SetActiveTab(topicTabs[idx])
+	return nil
+}
+
+func tabIndex(tabs []TabType, tab TabType) int {
+	for i, t := range tabs {
+		if t == tab {
+			return i
+		}
+	}
+	return 0
+}
+
+func (vm *TopicDetailViewModel) RenderTabs() string {
+	names := make([]string, len(topicTabs))
+	for i, t := range topicTabs {
+		names[i] = t.String()
+	}
+	return formatTabs(names, tabIndex(topicTabs, vm.GetActiveTab()))
+}
+
+func (vm *TopicDetailViewModel) defaultMessageQuery(topic string) models.MessageQuery {
+	return models.MessageQuery{
+		Topic:     topic,
+		Partition: models.AllPartitions,
+		Start:     models.StartLatest,
+		Limit:     messagePageSize,
+	}
+}
+
+func (vm *TopicDetailViewModel) resetMessagesLocked() {
+	vm.messages = nil
+	vm.messageQuery = nil
+	vm.pageHistory = nil
+	vm.nextOffsets = nil
+	vm.messagesLoading = false
+	vm.messagesGen++
+	vm.stopTailLocked()
+}
+
+func (vm *TopicDetailViewModel) stopTailLocked() {
+	if vm.tailCancel != nil {
+		vm.tailCancel()
+		vm.tailCancel = nil
+	}
+	if vm.tailPaused {
+		close(vm.tailResume)
+		vm.tailPaused = false
+	}
+	vm.tail = nil
+	vm.tailReceived = 0
+}
+
+func (vm *TopicDetailViewModel) IsFollowing() bool {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.tail != nil
+}
+
+// ToggleFollow starts streaming new records of the topic into the messages
+// tab, or goes back to paging when already following
+func (vm *TopicDetailViewModel) ToggleFollow() error {
+	vm.mu.RLock()
+	topic := vm.topic
+	following := vm.tail != nil
+	vm.mu.RUnlock()
+
+	if topic == nil {
+		return types.ErrNoSelection
+	}
+	if following {
+		vm.BrowseMessages(vm.defaultMessageQuery(topic.Name))
+		return nil
+	}
+	return vm.StartFollow()
+}
+
+func (vm *TopicDetailViewModel) StartFollow() error {
+	vm.mu.Lock()
+	topic := vm.topic
+	client := vm.kafkaClient
+	onError := vm.onError
+	if topic == nil || client == nil {
+		vm.mu.Unlock()
+		return types.ErrNoSelection
+	}
+
+	vm.resetMessagesLocked()
+	parent := vm.streamCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	vm.tailCancel = cancel
+	vm.tail = newRingBuffer[models.Message](tailBufferSize)
+	vm.activeTab = TabMessages
+	gen := vm.messagesGen
+	deserializer := vm.deserializer
+	vm.mu.Unlock()
+	vm.notifyChange(types.FieldItems)
+
+	go func() {
+		err := client.TailMessages(ctx, topic.Name, func(messages []models.Message) {
+			vm.waitWhilePaused(ctx)
+			decodeMessages(ctx, deserializer, topic.Name, messages)
+
+			vm.mu.Lock()
+			if gen != vm.messagesGen || vm.tail == nil {
+				vm.mu.Unlock()
+				return
+			}
+			vm.tail.Push(messages...)
+			vm.tailReceived += len(messages)
+			vm.mu.Unlock()
+			vm.notifyChange(types.FieldItems)
+		})
+
+		vm.mu.Lock()
+		if gen == vm.messagesGen {
+			vm.stopTailLocked()
+		}
+		vm.mu.Unlock()
+
+		if err != nil {
+			slog.Error("failed to follow topic", slog.String("topic", topic.Name), slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+		}
+		vm.notifyChange(types.FieldItems)
+	}()
+
+	return nil
+}
+
+// TogglePause stops or resumes consuming while following. Records are left
+// on the broker while paused, so nothing is dropped.
+func (vm *TopicDetailViewModel) TogglePause() error {
+	vm.mu.Lock()
+	if vm.tail == nil {
+		vm.mu.Unlock()
+		return types.ErrNoSelection
+	}
+	if vm.tailPaused {
+		close(vm.tailResume)
+		vm.tailPaused = false
+	} else {
+		vm.tailResume = make(chan struct{})
+		vm.tailPaused = true
+	}
+	vm.mu.Unlock()
+	vm.notifyChange(types.FieldItems)
+	return nil
+}
+
+func (vm *TopicDetailViewModel) waitWhilePaused(ctx context.Context) {
+	vm.mu.RLock()
+	paused := vm.tailPaused
+	resume := vm.tailResume
+	vm.mu.RUnlock()
+
+	if !paused {
+		return
+	}
+	select {
+	case <-resume:
+	case <-ctx.Done():
+	}
+}
+
+// BrowseMessages starts a new message listing from the given start position
+func (vm *TopicDetailViewModel) BrowseMessages(query models.MessageQuery) {
+	vm.mu.Lock()
+	vm.resetMessagesLocked()
+	vm.mu.Unlock()
+
+	vm.loadMessages(query)
+}
+
+func (vm *TopicDetailViewModel) NextPage() error {
+	vm.mu.Lock()
+	if vm.messageQuery == nil || vm.nextOffsets == nil || vm.messagesLoading {
+		vm.mu.Unlock()
+		return types.ErrNoSelection
+	}
+	vm.pageHistory = append(vm.pageHistory, *vm.messageQuery)
+	query := models.MessageQuery{
+		Topic:     vm.messageQuery.Topic,
+		Partition: vm.messageQuery.Partition,
+		Start:     models.StartFromOffsets,
+		Offsets:   vm.nextOffsets,
+		Limit:     messagePageSize,
+	}
+	vm.mu.Unlock()
+
+	vm.loadMessages(query)
+	return nil
+}
+
+func (vm *TopicDetailViewModel) PrevPage() error {
+	vm.mu.Lock()
+	if len(vm.pageHistory) == 0 || vm.messagesLoading {
+		vm.mu.Unlock()
+		return types.ErrNoSelection
+	}
+	query := vm.pageHistory[len(vm.pageHistory)-1]
+	vm.pageHistory = vm.pageHistory[:len(vm.pageHistory)-1]
+	vm.mu.Unlock()
+
+	vm.loadMessages(query)
+	return nil
+}
+
+func (vm *TopicDetailViewModel) loadMessages(query models.MessageQuery) {
+	vm.mu.Lock()
+	client := vm.kafkaClient
+	onError := vm.onError
+	vm.messageQuery = &query
+	vm.messagesLoading = client != nil
+	vm.messagesGen++
+	gen := vm.messagesGen
+	deserializer := vm.deserializer
+	vm.mu.Unlock()
+	vm.notifyChange(types.FieldItems)
+
+	if client == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), messagesTimeout)
+		defer cancel()
+
+		page, err := client.FetchMessages(ctx, query)
+		if err == nil {
+			decodeMessages(ctx, deserializer, query.Topic, page.Messages)
+		}
+
+		vm.mu.Lock()
+		if gen != vm.messagesGen {
+			vm.mu.Unlock()
+			return
+		}
+		vm.messagesLoading = false
+		if err == nil {
+			vm.messages = page.Messages
+			vm.nextOffsets = page.NextOffsets
+		}
+		vm.mu.Unlock()
+
+		if err != nil {
+			slog.Error("failed to load messages", slog.Any("error", err))
+			if onError != nil {
+				onError(err)
+			}
+		}
+		vm.notifyChange(types.FieldItems)
+	}()
+}
+
+// RenderMessagesTable renders the current page, or the newest buffered records
+// that fit into height while following
+func (vm *TopicDetailViewModel) RenderMessagesTable(width, height int) string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.topic == nil {
+		return "  Select a topic to view details"
+	}
+
+	var sb strings.Builder
+
+	if vm.tail != nil {
+		mode := "following"
+		if vm.tailPaused {
+			mode = "paused"
+		}
+		sb.WriteString(fmt.Sprintf("%-20s%-20s%-20s\n", "Mode", "Buffered", "Received"))
+		sb.WriteString(fmt.Sprintf("%-20s%-20s%-20d\n\n",
+			mode, fmt.Sprintf("%d/%d", vm.tail.Len(), vm.tail.Cap()), vm.tailReceived))
+
+		if vm.tail.Len() == 0 {
+			sb.WriteString("  Waiting for new messages...\n")
+			return sb.String()
+		}
+		// tabs, summary and table header take seven lines
+		writeMessageRows(&sb, vm.tail.Last(max(height-7, 1)), width)
+		return sb.String()
+	}
+
+	if vm.messageQuery != nil {
+		sb.WriteString(fmt.Sprintf("%-20s%-20s%-20s\n", "Start", "Partition", "Page"))
+		partition := "all"
+		if vm.messageQuery.Partition != models.AllPartitions {
+			partition = fmt.Sprintf("%d", vm.messageQuery.Partition)
+		}
+		sb.WriteString(fmt.Sprintf("%-20s%-20s%-20d\n\n",
+			describeMessageStart(vm.messageQuery), partition, len(vm.pageHistory)+1))
+	}
+
+	if vm.messagesLoading {
+		sb.WriteString("  Loading messages...\n")
+		return sb.String()
+	}
+	if len(vm.messages) == 0 {
+		sb.WriteString("  No messages\n")
+		return sb.String()
+	}
+
+	writeMessageRows(&sb, vm.messages, width)
+	return sb.String()
+}
+
+func writeMessageRows(sb *strings.Builder, messages []models.Message, width int) {
+	headers := []string{"Partition", "Offset", "Timestamp", "Key", "Headers", "Value"}
+	colWidths := []int{10, 12, 25, 20, 20, 0}
+	used := 0
+	for _, w := range colWidths[:len(colWidths)-1] {
+		used += w
+	}
+	colWidths[len(colWidths)-1] = max(width-used, 20)
+
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for _, m := range messages {
+		sb.WriteString(fmt.Sprintf("%-*d%-*d%-*s%-*s%-*s%s\n",
+			colWidths[0], m.Partition,
+			colWidths[1], m.Offset,
+			colWidths[2], m.Timestamp.Format("2006-01-02 15:04:05.000"),
+			colWidths[3], truncate(formatPayload(m.DecodedKey, m.Key), colWidths[3]-1),
+			colWidths[4], truncate(formatHeaders(m.Headers), colWidths[4]-1),
+			truncate(formatPayload(m.DecodedValue, m.Value), colWidths[5]-1),
+		))
+	}
+}
+
+func describeMessageStart(q *models.MessageQuery) string {
+	switch q.Start {
+	case models.StartLatest:
+		return fmt.Sprintf("latest %d", q.Limit)
+	case models.StartOffset:
+		return fmt.Sprintf("offset %d", q.Offset)
+	case models.StartTimestamp:
+		return time.UnixMilli(q.Timestamp).Format("2006-01-02 15:04:05")
+	}
+	return q.Start.String()
+}
+
+// decodeMessages decodes keys and values written with a schema from the
+// registry. Each is decoded on its own, as keys and values usually have
+// different schemas or none at all. Anything that fails to decode is shown
+// as it is.
+func decodeMessages(ctx context.Context, deserializer *schemaregistry.Deserializer, topic string, messages []models.Message) {
+	if deserializer == nil {
+		return
+	}
+	decode := func(m *models.Message, data []byte) string {
+		if !schemaregistry.IsWireFormat(data) {
+			return ""
+		}
+		decoded, err := deserializer.Decode(ctx, topic, data)
+		if err != nil {
+			slog.Warn("failed to decode message", slog.String("topic", topic),
+				slog.Int("partition", m.Partition), slog.Int64("offset", m.Offset), slog.Any("error", err))
+		}
+		return decoded
+	}
+	for i := range messages {
+		m := &messages[i]
+		m.DecodedKey = decode(m, m.Key)
+		m.DecodedValue = decode(m, m.Value)
+	}
+}
+
+func formatPayload(decoded string, raw []byte) string {
+	if decoded != "" {
+		return decoded
+	}
+	return formatBytes(raw)
+}
+
+// formatBytes renders printable UTF-8 as text and anything else as hex
+func formatBytes(b []byte) string {
+	if b == nil {
+		return "<null>"
+	}
+	if utf8.Valid(b) {
+		printable := true
+		for _, r := range string(b) {
+			if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+				printable = false
+				break
+			}
+		}
+		if printable {
+			return strings.Join(strings.Fields(string(b)), " ")
+		}
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+func formatHeaders(headers []models.MessageHeader) string {
+	parts := make([]string, len(headers))
+	for i, h := range headers {
+		parts[i] = h.Key + "=" + formatBytes(h.Value)
+	}
+	return strings.Join(parts, ",")
+}
+
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
+
+const (
+	colorOverride = "\x1b[33m"
+	colorReset    = "\x1b[0m"
+)
+
+// RenderConfigTable lists every topic config, highlighting values overridden
+// on the topic itself
+func (vm *TopicDetailViewModel) RenderConfigTable(width int) string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.topic == nil {
+		return "  Select a topic to view details"
+	}
+	if len(vm.configs) == 0 {
+		return "  No configs\n"
+	}
+
+	overrides := 0
+	for _, c := range vm.configs {
+		if c.IsOverride() {
+			overrides++
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-20s%-20s\n", "Configs", "Overrides"))
+	sb.WriteString(fmt.Sprintf("%-20d%-20d\n\n", len(vm.configs), overrides))
+
+	headers := []string{"  Name", "Source", "Sensitive", "Value"}
+	colWidths := []int{42, 24, 11, 0}
+	colWidths[3] = max(width-colWidths[0]-colWidths[1]-colWidths[2], 20)
+
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for _, c := range vm.configs {
+		marker := "  "
+		if c.IsOverride() {
+			marker = "* "
+		}
+		sensitive := "no"
+		value := c.Value
+		if c.Sensitive {
+			sensitive = "yes"
+			value = "******"
+		}
+
+		line := fmt.Sprintf("%-*s%-*s%-*s%s",
+			colWidths[0], truncate(marker+c.Name, colWidths[0]-1),
+			colWidths[1], c.Source,
+			colWidths[2], sensitive,
+			truncate(value, colWidths[3]-1),
+		)
+		if c.IsOverride() {
+			line = colorOverride + line + colorReset
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	return sb.String()
 }
 
 func (vm *TopicDetailViewModel) RenderPartitionsTable(width int) string {
@@ -18,6 +18,7 @@ type TopicsViewModel struct {
 	mu                 sync.RWMutex
 	topics             []models.Topic
 	selectedIndex      int
+	marked             map[string]bool
 	onChange           types.OnChangeFunc
 	commandBindings    []*types.CommandBinding
 	onSelectionChanged SelectionChangedFunc
@@ -28,16 +29,19 @@ type TopicsViewModel struct {
 func NewTopicsViewModel() *TopicsViewModel {
 	vm := &TopicsViewModel{
 		selectedIndex: -1,
+		marked:        make(map[string]bool),
 	}
 
 	moveUp := types.NewCommand(vm.MoveUp)
 	moveDown := types.NewCommand(vm.MoveDown)
+	toggleMark := types.NewCommand(vm.ToggleMark)
 
 	vm.commandBindings = []*types.CommandBinding{
 		{Key: 'k', Cmd: moveUp},
 		{Key: 'j', Cmd: moveDown},
 		{Key: gocui.KeyArrowUp, Cmd: moveUp},
 		{Key: gocui.KeyArrowDown, Cmd: moveDown},
+		{Key: gocui.KeySpace, Cmd: toggleMark},
 	}
 
 	return vm
@@ -128,11 +132,86 @@ func (vm *TopicsViewModel) GetDisplayItems() []string {
 
 	items := make([]string, len(vm.topics))
 	for i, t := range vm.topics {
-		items[i] = fmt.Sprintf("%s (P:%d R:%d)", t.Name, t.Partitions, t.Replicas)
+		mark := ""
+		if vm.marked[t.Name] {
+			mark = "* "
+		}
+		items[i] = fmt.Sprintf("%s%s (P:%d R:%d)", mark, t.Name, t.Partitions, t.Replicas)
 	}
 	return items
 }
 
+// ToggleMark marks or unmarks the selected topic for bulk actions
+func (vm *TopicsViewModel) ToggleMark() error {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	if vm.selectedIndex < 0 || vm.selectedIndex >= len(vm.topics) {
+		return types.ErrNoSelection
+	}
+	name := vm.topics[vm.selectedIndex].Name
+	if vm.marked[name] {
+		delete(vm.marked, name)
+	} else {
+		vm.marked[name] = true
+	}
+	return nil
+}
+
+// GetMarkedTopics returns the marked topic names in list order
+func (vm *TopicsViewModel) GetMarkedTopics() []string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	var names []string
+	for _, t := range vm.topics {
+		if vm.marked[t.Name] {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+func (vm *TopicsViewModel) UpdatePartitions(ctx context.Context, topic string, total int) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+	slog.Info("update partitions", slog.String("topic", topic), slog.Int("total", total))
+
+	if err := client.UpdatePartitions(ctx, topic, total); err != nil {
+		return err
+	}
+
+	vm.Reload()
+	return nil
+}
+
+func (vm *TopicsViewModel) DeleteTopics(ctx context.Context, names []string) error {
+	vm.mu.RLock()
+	client := vm.kafkaClient
+	vm.mu.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("no active kafka client")
+	}
+	slog.Info("delete topics", slog.Any("topics", names))
+
+	err := client.DeleteTopics(ctx, names...)
+
+	vm.mu.Lock()
+	for _, name := range names {
+		delete(vm.marked, name)
+	}
+	vm.mu.Unlock()
+
+	vm.Reload()
+	return err
+}
+
 func (vm *TopicsViewModel) GetTitle() string {
 	return "Topics"
 }
@@ -153,12 +232,37 @@ func (vm *TopicsViewModel) GetSelectedTopic() *models.Topic {
 
 func (vm *TopicsViewModel) Load(topics []models.Topic) {
 	vm.mu.Lock()
+	selected := 0
+	if vm.selectedIndex >= 0 && vm.selectedIndex < len(vm.topics) {
+		// keep the selection on the same topic across reloads
+		name := vm.topics[vm.selectedIndex].Name
+		for i, t := range topics {
+			if t.Name == name {
+				selected = i
+				break
+			}
+		}
+	}
 	vm.topics = topics
 	vm.selectedIndex = -1
+	marked := make(map[string]bool)
+	for _, t := range topics {
+		if vm.marked[t.Name] {
+			marked[t.Name] = true
+		}
+	}
+	vm.marked = marked
+	callback := vm.onSelectionChanged
 	vm.mu.Unlock()
 
 	vm.notifyChange(types.FieldItems)
-	vm.SetSelectedIndex(0)
+	if len(topics) == 0 {
+		if callback != nil {
+			callback(nil)
+		}
+		return
+	}
+	vm.SetSelectedIndex(selected)
 }
 
 func (vm *TopicsViewModel) SetKafkaClient(client kafka.KafkaClient) {
@@ -0,0 +1,336 @@
+package viewmodel
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jurabek/lazykafka/internal/models"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+)
+
+const (
+	StepTruncateScope     = 0
+	StepTruncatePartition = 1
+	StepTruncateTarget    = 2
+	StepTruncateValue     = 3
+	StepTruncatePreview   = 4
+)
+
+type TruncatePreviewFunc func(spec models.TruncateSpec) ([]models.TruncatePreview, error)
+
+// TruncateViewModel backs the popup deleting the records of a topic up to
+// its end, an offset or a timestamp
+type TruncateViewModel struct {
+	mu            sync.RWMutex
+	topic         string
+	allPartitions bool
+	partition     string
+	target        models.TruncateTarget
+	value         string
+	previews      []models.TruncatePreview
+	previewErr    error
+	currentStep   int
+	onChange      types.OnChangeFunc
+	preview       TruncatePreviewFunc
+	onSubmit      func(topic string, previews []models.TruncatePreview)
+	onCancel      func()
+}
+
+func NewTruncateViewModel(
+	topic string,
+	preview TruncatePreviewFunc,
+	onSubmit func(string, []models.TruncatePreview),
+	onCancel func(),
+) *TruncateViewModel {
+	return &TruncateViewModel{
+		topic:         topic,
+		allPartitions: true,
+		partition:     "0",
+		target:        models.TruncateToEnd,
+		currentStep:   StepTruncateScope,
+		preview:       preview,
+		onSubmit:      onSubmit,
+		onCancel:      onCancel,
+	}
+}
+
+func (vm *TruncateViewModel) SetOnChange(fn types.OnChangeFunc) {
+	vm.onChange = fn
+}
+
+func (vm *TruncateViewModel) notifyChange(fieldName string) {
+	if vm.onChange != nil {
+		vm.onChange(types.ChangeEvent{FieldName: fieldName})
+	}
+}
+
+func (vm *TruncateViewModel) GetCurrentStep() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.currentStep
+}
+
+func (vm *TruncateViewModel) GetStepTitle() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	switch vm.currentStep {
+	case StepTruncateScope:
+		return fmt.Sprintf("Truncate %s (↑↓ to select, Enter to confirm):", vm.topic)
+	case StepTruncatePartition:
+		return "Partition:"
+	case StepTruncateTarget:
+		return "Delete records up to (↑↓ to select, Enter to confirm):"
+	case StepTruncateValue:
+		if vm.target == models.TruncateToTimestamp {
+			return "Timestamp (RFC3339, 2006-01-02 15:04:05 or unix millis):"
+		}
+		return "Offset (records before it are deleted):"
+	case StepTruncatePreview:
+		return "Dry run (Enter to continue, Esc to cancel):"
+	}
+	return ""
+}
+
+func (vm *TruncateViewModel) NextStep() bool {
+	vm.mu.Lock()
+
+	switch vm.currentStep {
+	case StepTruncateScope:
+		if vm.allPartitions {
+			vm.currentStep = StepTruncateTarget
+		} else {
+			vm.currentStep = StepTruncatePartition
+		}
+	case StepTruncatePartition:
+		vm.currentStep = StepTruncateTarget
+	case StepTruncateTarget:
+		if vm.target == models.TruncateToEnd {
+			vm.currentStep = StepTruncatePreview
+		} else {
+			vm.currentStep = StepTruncateValue
+		}
+	case StepTruncateValue:
+		vm.currentStep = StepTruncatePreview
+	case StepTruncatePreview:
+		done := vm.previewErr == nil && vm.deletedRecordsLocked() > 0
+		vm.mu.Unlock()
+		return done // done, submit
+	}
+
+	step := vm.currentStep
+	vm.mu.Unlock()
+
+	if step == StepTruncatePreview {
+		vm.runPreview()
+	}
+	return false
+}
+
+func (vm *TruncateViewModel) GetScopeOptions() []string {
+	return []string{"All partitions", "Single partition"}
+}
+
+func (vm *TruncateViewModel) GetSelectedScopeIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.allPartitions {
+		return 0
+	}
+	return 1
+}
+
+// ToggleScope switches between all partitions and a single one
+func (vm *TruncateViewModel) ToggleScope() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.allPartitions = !vm.allPartitions
+	vm.notifyChange("scope")
+}
+
+func (vm *TruncateViewModel) GetTargetOptions() []string {
+	return []string{"End (delete everything)", "Specific offset", "Timestamp"}
+}
+
+func (vm *TruncateViewModel) GetSelectedTargetIndex() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return int(vm.target)
+}
+
+func (vm *TruncateViewModel) MoveTargetUp() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.target > 0 {
+		vm.target--
+	} else {
+		vm.target = models.TruncateToTimestamp
+	}
+	vm.notifyChange("target")
+}
+
+func (vm *TruncateViewModel) MoveTargetDown() {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	if vm.target < models.TruncateToTimestamp {
+		vm.target++
+	} else {
+		vm.target = models.TruncateToEnd
+	}
+	vm.notifyChange("target")
+}
+
+func (vm *TruncateViewModel) SetPartition(p string) {
+	vm.mu.Lock()
+	vm.partition = p
+	vm.mu.Unlock()
+}
+
+func (vm *TruncateViewModel) GetPartition() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	return vm.partition
+}
+
+func (vm *TruncateViewModel) SetValue(v string) {
+	vm.mu.Lock()
+	vm.value = v
+	vm.mu.Unlock()
+}
+
+func (vm *TruncateViewModel) BuildSpec() (models.TruncateSpec, error) {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	spec := models.TruncateSpec{
+		Topic:     vm.topic,
+		Partition: models.AllPartitions,
+		Target:    vm.target,
+	}
+
+	if !vm.allPartitions {
+		partition, err := strconv.Atoi(strings.TrimSpace(vm.partition))
+		if err != nil || partition < 0 {
+			return spec, errors.Join(ErrValidation, errors.New("partition must be a non-negative integer"))
+		}
+		spec.Partition = partition
+	}
+
+	value := strings.TrimSpace(vm.value)
+	switch vm.target {
+	case models.TruncateToOffset:
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || offset < 0 {
+			return spec, errors.Join(ErrValidation, errors.New("offset must be a non-negative integer"))
+		}
+		spec.Offset = offset
+	case models.TruncateToTimestamp:
+		ts, err := models.ParseTimestamp(value)
+		if err != nil {
+			return spec, errors.Join(ErrValidation, err)
+		}
+		spec.Timestamp = ts
+	}
+
+	return spec, nil
+}
+
+func (vm *TruncateViewModel) runPreview() {
+	spec, err := vm.BuildSpec()
+
+	var previews []models.TruncatePreview
+	if err == nil && vm.preview != nil {
+		previews, err = vm.preview(spec)
+	}
+
+	vm.mu.Lock()
+	vm.previews = previews
+	vm.previewErr = err
+	vm.mu.Unlock()
+	vm.notifyChange("previews")
+}
+
+func (vm *TruncateViewModel) deletedRecordsLocked() int64 {
+	var total int64
+	for _, p := range vm.previews {
+		total += p.DeletedRecords()
+	}
+	return total
+}
+
+func (vm *TruncateViewModel) GetPreviewLineCount() int {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+	if vm.previewErr != nil || vm.deletedRecordsLocked() == 0 {
+		return 1
+	}
+	return len(vm.previews) + 3
+}
+
+func (vm *TruncateViewModel) RenderPreview() string {
+	vm.mu.RLock()
+	defer vm.mu.RUnlock()
+
+	if vm.previewErr != nil {
+		return fmt.Sprintf(" Error: %s", strings.ReplaceAll(vm.previewErr.Error(), "\n", ": "))
+	}
+	total := vm.deletedRecordsLocked()
+	if total == 0 {
+		return " Nothing to delete"
+	}
+
+	var sb strings.Builder
+
+	headers := []string{"Partition", "Start Offset", "New Start", "End Offset", "Deleted"}
+	colWidths := []int{12, 16, 16, 16, 14}
+
+	sb.WriteString(" ")
+	for i, h := range headers {
+		sb.WriteString(fmt.Sprintf("%-*s", colWidths[i], h))
+	}
+	sb.WriteString("\n ")
+
+	for i := range headers {
+		sb.WriteString(strings.Repeat("-", colWidths[i]-1))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("\n")
+
+	for _, p := range vm.previews {
+		sb.WriteString(fmt.Sprintf(" %-*d%-*d%-*d%-*d%-*d\n",
+			colWidths[0], p.Partition,
+			colWidths[1], p.StartOffset,
+			colWidths[2], p.NewStartOffset,
+			colWidths[3], p.EndOffset,
+			colWidths[4], p.DeletedRecords(),
+		))
+	}
+	sb.WriteString(fmt.Sprintf(" %d records will be deleted permanently\n", total))
+
+	return sb.String()
+}
+
+func (vm *TruncateViewModel) Submit() error {
+	vm.mu.RLock()
+	if vm.previewErr != nil {
+		err := vm.previewErr
+		vm.mu.RUnlock()
+		return err
+	}
+	topic := vm.topic
+	previews := vm.previews
+	vm.mu.RUnlock()
+
+	if vm.onSubmit != nil {
+		vm.onSubmit(topic, previews)
+	}
+	return nil
+}
+
+func (vm *TruncateViewModel) Cancel() {
+	if vm.onCancel != nil {
+		vm.onCancel()
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/jroimartin/gocui"
+	"github.com/jurabek/lazykafka/internal/tui/types"
 	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
 )
 
@@ -22,6 +23,7 @@ type wizardEditor struct {
 	onEnter     func()
 	onArrowUp   func()
 	onArrowDown func()
+	onSave      func()
 	view        *AddBrokerView
 }
 
@@ -50,11 +52,15 @@ func (e *wizardEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Mod
 	}
 
 	// Prevent text input during list selection steps
-	if e.view != nil {
-		step := e.view.viewModel.GetCurrentStep()
-		if step == viewmodel.StepAuthType || step == viewmodel.StepSASLMechanism {
-			return
+	if e.view != nil && e.view.viewModel.GetCurrentStep() == viewmodel.StepTestConnection {
+		if ch == 's' && e.onSave != nil {
+			e.onSave()
 		}
+		return
+	}
+
+	if e.view != nil && e.view.viewModel.IsSelectionStep() {
+		return
 	}
 
 	gocui.DefaultEditor.Edit(v, key, ch, mod)
@@ -79,6 +85,20 @@ func (v *AddBrokerView) GetViewModel() *viewmodel.AddBrokerViewModel {
 
 func (v *AddBrokerView) Initialize(g *gocui.Gui) error {
 	v.gui = g
+	v.viewModel.SetOnChange(func(event types.ChangeEvent) {
+		if event.FieldName != "test" {
+			return
+		}
+		g.Update(func(g *gocui.Gui) error {
+			inputView, err := g.View(wizardInput)
+			if err != nil || v.viewModel.GetCurrentStep() != viewmodel.StepTestConnection {
+				return nil
+			}
+			inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+			v.renderTestResult(inputView)
+			return nil
+		})
+	})
 	return v.render()
 }
 
@@ -89,10 +109,12 @@ func (v *AddBrokerView) render() error {
 	step := v.viewModel.GetCurrentStep()
 	var wizardHeight int
 	switch step {
-	case viewmodel.StepAuthType:
+	case viewmodel.StepAuthType, viewmodel.StepTLS, viewmodel.StepTLSVerify, viewmodel.StepSecretBackend:
 		wizardHeight = 4 // 2 options + title + padding
 	case viewmodel.StepSASLMechanism:
 		wizardHeight = 6 // 4 options + title + padding
+	case viewmodel.StepTestConnection:
+		wizardHeight = 7 // 4 result lines, room for wrapped errors
 	default:
 		wizardHeight = 2 // standard input height
 	}
@@ -113,8 +135,11 @@ func (v *AddBrokerView) render() error {
 		onEnter:     v.handleEnter,
 		onArrowUp:   v.handleArrowUp,
 		onArrowDown: v.handleArrowDown,
+		onSave:      v.handleSave,
 		view:        v,
 	}
+	inputView.Mask = 0
+	inputView.Wrap = step == viewmodel.StepTestConnection
 
 	// Handle different step rendering
 	switch step {
@@ -124,13 +149,21 @@ func (v *AddBrokerView) render() error {
 	case viewmodel.StepSASLMechanism:
 		v.renderSASLMechanismList(inputView)
 		v.gui.Cursor = false
+	case viewmodel.StepTLS, viewmodel.StepTLSVerify, viewmodel.StepSecretBackend:
+		v.renderToggleList(inputView)
+		v.gui.Cursor = false
+	case viewmodel.StepTestConnection:
+		v.renderTestResult(inputView)
+		v.gui.Cursor = false
 	default:
 		inputView.SetCursor(0, 0)
+		if value := v.viewModel.GetStepValue(); value != "" && inputView.Buffer() == "" {
+			fmt.Fprint(inputView, value)
+			inputView.SetCursor(len(value), 0)
+		}
 		v.gui.Cursor = true
 		if step == viewmodel.StepPassword {
 			inputView.Mask = '*'
-		} else {
-			inputView.Mask = 0
 		}
 	}
 
@@ -157,23 +190,43 @@ func (v *AddBrokerView) handleEsc() {
 }
 
 func (v *AddBrokerView) handleEnter() {
-	step := v.viewModel.GetCurrentStep()
-
-	if step == viewmodel.StepAuthType || step == viewmodel.StepSASLMechanism {
-		if v.viewModel.NextStep() {
+	if v.viewModel.GetCurrentStep() == viewmodel.StepTestConnection {
+		switch {
+		case v.viewModel.IsTesting():
+		case v.viewModel.TestPassed():
 			_ = v.viewModel.Submit()
-		} else {
-			v.clearAndRender()
+		default:
+			v.viewModel.TestConnection()
 		}
 		return
 	}
 
-	v.saveCurrentValue()
+	if !v.viewModel.IsSelectionStep() {
+		v.saveCurrentValue()
+	}
 
 	if v.viewModel.NextStep() {
 		_ = v.viewModel.Submit()
-	} else {
-		v.clearAndRender()
+		return
+	}
+	v.clearAndRender()
+	if v.viewModel.GetCurrentStep() == viewmodel.StepTestConnection {
+		v.viewModel.TestConnection()
+	}
+}
+
+// handleSave saves a config whose connection test failed
+func (v *AddBrokerView) handleSave() {
+	if v.viewModel.IsTesting() || v.viewModel.TestPassed() {
+		return
+	}
+	_ = v.viewModel.Submit()
+}
+
+func (v *AddBrokerView) renderTestResult(inputView *gocui.View) {
+	inputView.Clear()
+	for _, line := range v.viewModel.RenderTestResult() {
+		fmt.Fprintln(inputView, line)
 	}
 }
 
@@ -186,6 +239,8 @@ func (v *AddBrokerView) handleArrowUp() {
 	case viewmodel.StepSASLMechanism:
 		v.viewModel.MoveSASLMechanismUp()
 		v.updateSASLMechanismDisplay()
+	case viewmodel.StepTLS, viewmodel.StepTLSVerify, viewmodel.StepSecretBackend:
+		v.toggleOption()
 	}
 }
 
@@ -198,6 +253,8 @@ func (v *AddBrokerView) handleArrowDown() {
 	case viewmodel.StepSASLMechanism:
 		v.viewModel.MoveSASLMechanismDown()
 		v.updateSASLMechanismDisplay()
+	case viewmodel.StepTLS, viewmodel.StepTLSVerify, viewmodel.StepSecretBackend:
+		v.toggleOption()
 	}
 }
 
@@ -229,6 +286,48 @@ func (v *AddBrokerView) renderSASLMechanismList(inputView *gocui.View) {
 	}
 }
 
+// toggleOption flips the two-option steps, so up and down behave the same
+func (v *AddBrokerView) toggleOption() {
+	inputView, err := v.gui.View(wizardInput)
+	if err != nil {
+		return
+	}
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepTLS:
+		v.viewModel.ToggleTLS()
+	case viewmodel.StepTLSVerify:
+		v.viewModel.ToggleTLSVerify()
+	case viewmodel.StepSecretBackend:
+		v.viewModel.ToggleSecretBackend()
+	}
+	v.renderToggleList(inputView)
+}
+
+func (v *AddBrokerView) renderToggleList(inputView *gocui.View) {
+	inputView.Clear()
+	var options []string
+	var selectedIdx int
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepTLS:
+		options = v.viewModel.GetTLSOptions()
+		selectedIdx = v.viewModel.GetSelectedTLSIndex()
+	case viewmodel.StepTLSVerify:
+		options = v.viewModel.GetTLSVerifyOptions()
+		selectedIdx = v.viewModel.GetSelectedTLSVerifyIndex()
+	case viewmodel.StepSecretBackend:
+		options = v.viewModel.GetSecretBackendOptions()
+		selectedIdx = v.viewModel.GetSelectedSecretBackendIndex()
+	}
+
+	for i, option := range options {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
 func (v *AddBrokerView) updateSASLMechanismDisplay() {
 	inputView, err := v.gui.View(wizardInput)
 	if err != nil {
@@ -253,6 +352,16 @@ func (v *AddBrokerView) saveCurrentValue() {
 		v.viewModel.SetUsername(value)
 	case viewmodel.StepPassword:
 		v.viewModel.SetPassword(value)
+	case viewmodel.StepOAuthTokenURL:
+		v.viewModel.SetOAuthTokenURL(value)
+	case viewmodel.StepTLSCAFile:
+		v.viewModel.SetTLSCAFile(value)
+	case viewmodel.StepTLSCertFile:
+		v.viewModel.SetTLSCertFile(value)
+	case viewmodel.StepTLSKeyFile:
+		v.viewModel.SetTLSKeyFile(value)
+	case viewmodel.StepTLSServerName:
+		v.viewModel.SetTLSServerName(value)
 	}
 }
 
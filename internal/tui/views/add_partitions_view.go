@@ -0,0 +1,147 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const addPartitionsInput = "add_partitions_input"
+
+type addPartitionsEditor struct {
+	onEsc   func()
+	onEnter func()
+	view    *AddPartitionsView
+}
+
+func (e *addPartitionsEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	}
+
+	// Prevent text input during confirmation
+	if e.view != nil && e.view.viewModel.GetCurrentStep() == viewmodel.StepPartitionConfirm {
+		return
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type AddPartitionsView struct {
+	viewModel *viewmodel.AddPartitionsViewModel
+	gui       *gocui.Gui
+}
+
+func NewAddPartitionsView(vm *viewmodel.AddPartitionsViewModel) *AddPartitionsView {
+	return &AddPartitionsView{
+		viewModel: vm,
+	}
+}
+
+func (v *AddPartitionsView) GetViewModel() *viewmodel.AddPartitionsViewModel {
+	return v.viewModel
+}
+
+func (v *AddPartitionsView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *AddPartitionsView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	height := 2
+	if step == viewmodel.StepPartitionConfirm {
+		height = v.viewModel.GetWarningLineCount() + 1
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(addPartitionsInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Editor = &addPartitionsEditor{
+		onEsc:   v.handleEsc,
+		onEnter: v.handleEnter,
+		view:    v,
+	}
+
+	if step == viewmodel.StepPartitionConfirm {
+		inputView.Clear()
+		fmt.Fprint(inputView, v.viewModel.RenderWarning())
+		v.gui.Cursor = false
+	} else {
+		v.gui.Cursor = true
+		if inputView.Buffer() == "" {
+			count := v.viewModel.GetCount()
+			fmt.Fprint(inputView, count)
+			inputView.SetCursor(len(count), 0)
+		}
+	}
+
+	_, _ = v.gui.SetViewOnTop(addPartitionsInput)
+
+	if _, err := v.gui.SetCurrentView(addPartitionsInput); err != nil {
+		slog.Error("failed to set current view", "view", addPartitionsInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *AddPartitionsView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *AddPartitionsView) handleEnter() {
+	inputView, err := v.gui.View(addPartitionsInput)
+	if err != nil {
+		return
+	}
+
+	step := v.viewModel.GetCurrentStep()
+	if step == viewmodel.StepPartitionCount {
+		v.viewModel.SetCount(strings.TrimSpace(inputView.Buffer()))
+	}
+
+	if v.viewModel.NextStep() {
+		if err := v.viewModel.Submit(); err != nil {
+			slog.Error("failed to add partitions", "error", err)
+		}
+		return
+	}
+
+	if v.viewModel.GetCurrentStep() == step {
+		// invalid count, the title shows why
+		inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+		return
+	}
+
+	inputView.Clear()
+	inputView.SetCursor(0, 0)
+	_ = v.render()
+}
+
+func (v *AddPartitionsView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(addPartitionsInput)
+	return nil
+}
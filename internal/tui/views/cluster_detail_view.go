@@ -0,0 +1,73 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+	"github.com/jurabek/lazykafka/internal/tui/types"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+type ClusterDetailView struct {
+	BaseView
+	viewModel *viewmodel.ClusterDetailViewModel
+}
+
+func NewClusterDetailView(vm *viewmodel.ClusterDetailViewModel) *ClusterDetailView {
+	return &ClusterDetailView{
+		BaseView:  BaseView{viewModel: vm},
+		viewModel: vm,
+	}
+}
+
+func (v *ClusterDetailView) Initialize(g *gocui.Gui) (bool, error) {
+	x0, y0, x1, y1 := v.GetBounds()
+
+	view, err := g.SetView(v.viewModel.GetName(), x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return false, err
+	}
+
+	created := err == gocui.ErrUnknownView
+	if created {
+		view.Title = v.viewModel.GetTitle()
+		view.Wrap = false
+	}
+
+	return created, nil
+}
+
+func (v *ClusterDetailView) Render(g *gocui.Gui, gocuiView *gocui.View) error {
+	gocuiView.Clear()
+	gocuiView.Title = v.viewModel.GetTitle()
+
+	maxX, _ := gocuiView.Size()
+	fmt.Fprint(gocuiView, v.viewModel.RenderTabs())
+
+	var content string
+	switch v.viewModel.GetActiveTab() {
+	case viewmodel.ClusterTabAPIVersions:
+		content = v.viewModel.RenderAPIVersionsTable(maxX)
+	default:
+		content = v.viewModel.RenderBrokersTable(maxX)
+	}
+	fmt.Fprint(gocuiView, content)
+
+	return nil
+}
+
+func (v *ClusterDetailView) Destroy(g *gocui.Gui) error {
+	return g.DeleteView(v.viewModel.GetName())
+}
+
+func (v *ClusterDetailView) SetupCallbacks(g *gocui.Gui) {
+	v.viewModel.SetOnChange(func(event types.ChangeEvent) {
+		g.Update(func(gui *gocui.Gui) error {
+			view, err := g.View(v.viewModel.GetName())
+			if err != nil {
+				return nil
+			}
+			return v.Render(g, view)
+		})
+	})
+}
@@ -0,0 +1,175 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const confirmInput = "confirm_input"
+
+type confirmEditor struct {
+	onEsc       func()
+	onEnter     func()
+	onArrowUp   func()
+	onArrowDown func()
+	view        *ConfirmView
+}
+
+func (e *confirmEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp:
+		if e.onArrowUp != nil {
+			e.onArrowUp()
+		}
+		return
+	case gocui.KeyArrowDown:
+		if e.onArrowDown != nil {
+			e.onArrowDown()
+		}
+		return
+	}
+
+	if e.view != nil && e.view.viewModel.GetCurrentStep() == viewmodel.StepConfirmSelect {
+		return
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type ConfirmView struct {
+	viewModel *viewmodel.ConfirmViewModel
+	gui       *gocui.Gui
+}
+
+func NewConfirmView(vm *viewmodel.ConfirmViewModel) *ConfirmView {
+	return &ConfirmView{
+		viewModel: vm,
+	}
+}
+
+func (v *ConfirmView) GetViewModel() *viewmodel.ConfirmViewModel {
+	return v.viewModel
+}
+
+func (v *ConfirmView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *ConfirmView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	height := 2
+	if step == viewmodel.StepConfirmSelect {
+		height = min(len(v.viewModel.GetOptions())+1, maxY-4)
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(confirmInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Editor = &confirmEditor{
+		onEsc:       v.handleEsc,
+		onEnter:     v.handleEnter,
+		onArrowUp:   v.handleArrowUp,
+		onArrowDown: v.handleArrowDown,
+		view:        v,
+	}
+
+	if step == viewmodel.StepConfirmSelect {
+		v.renderOptions(inputView)
+		v.gui.Cursor = false
+	} else {
+		v.gui.Cursor = true
+	}
+
+	_, _ = v.gui.SetViewOnTop(confirmInput)
+
+	if _, err := v.gui.SetCurrentView(confirmInput); err != nil {
+		slog.Error("failed to set current view", "view", confirmInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *ConfirmView) renderOptions(inputView *gocui.View) {
+	inputView.Clear()
+	selectedIdx := v.viewModel.GetSelectedIndex()
+	for i, option := range v.viewModel.GetOptions() {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
+func (v *ConfirmView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *ConfirmView) handleEnter() {
+	inputView, err := v.gui.View(confirmInput)
+	if err != nil {
+		return
+	}
+
+	if v.viewModel.GetCurrentStep() == viewmodel.StepConfirmSelect {
+		v.viewModel.NextStep()
+		inputView.Clear()
+		inputView.SetCursor(0, 0)
+		_ = v.render()
+		return
+	}
+
+	if err := v.viewModel.Submit(inputView.Buffer()); err != nil {
+		inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	}
+}
+
+func (v *ConfirmView) handleArrowUp() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepConfirmSelect {
+		return
+	}
+	v.viewModel.MoveUp()
+	if inputView, err := v.gui.View(confirmInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *ConfirmView) handleArrowDown() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepConfirmSelect {
+		return
+	}
+	v.viewModel.MoveDown()
+	if inputView, err := v.gui.View(confirmInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *ConfirmView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(confirmInput)
+	return nil
+}
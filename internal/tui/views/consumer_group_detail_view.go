@@ -42,7 +42,15 @@ func (v *ConsumerGroupDetailView) Render(g *gocui.Gui, gocuiView *gocui.View) er
 	gocuiView.Title = v.viewModel.GetTitle()
 
 	maxX, _ := gocuiView.Size()
-	content := v.viewModel.RenderOffsetsTable(maxX)
+	fmt.Fprint(gocuiView, v.viewModel.RenderTabs())
+
+	var content string
+	switch v.viewModel.GetActiveTab() {
+	case viewmodel.CGTabMembers:
+		content = v.viewModel.RenderMembersTable(maxX)
+	default:
+		content = v.viewModel.RenderOffsetsTable(maxX)
+	}
 	fmt.Fprint(gocuiView, content)
 
 	return nil
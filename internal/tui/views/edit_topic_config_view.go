@@ -0,0 +1,207 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const editTopicConfigInput = "edit_topic_config_input"
+
+type editTopicConfigEditor struct {
+	onEsc       func()
+	onEnter     func()
+	onArrowUp   func()
+	onArrowDown func()
+	view        *EditTopicConfigView
+}
+
+func (e *editTopicConfigEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp:
+		if e.onArrowUp != nil {
+			e.onArrowUp()
+		}
+		return
+	case gocui.KeyArrowDown:
+		if e.onArrowDown != nil {
+			e.onArrowDown()
+		}
+		return
+	}
+
+	// Prevent text input during operation selection and diff steps
+	if e.view != nil {
+		switch e.view.viewModel.GetCurrentStep() {
+		case viewmodel.StepConfigOp, viewmodel.StepConfigDiff:
+			return
+		}
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type EditTopicConfigView struct {
+	viewModel *viewmodel.EditTopicConfigViewModel
+	gui       *gocui.Gui
+}
+
+func NewEditTopicConfigView(vm *viewmodel.EditTopicConfigViewModel) *EditTopicConfigView {
+	return &EditTopicConfigView{
+		viewModel: vm,
+	}
+}
+
+func (v *EditTopicConfigView) GetViewModel() *viewmodel.EditTopicConfigViewModel {
+	return v.viewModel
+}
+
+func (v *EditTopicConfigView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *EditTopicConfigView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	var height int
+	switch step {
+	case viewmodel.StepConfigOp:
+		height = len(v.viewModel.GetOpOptions()) + 1
+	case viewmodel.StepConfigDiff:
+		height = v.viewModel.GetDiffLineCount() + 1
+	default:
+		height = 2
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(editTopicConfigInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Editor = &editTopicConfigEditor{
+		onEsc:       v.handleEsc,
+		onEnter:     v.handleEnter,
+		onArrowUp:   v.handleArrowUp,
+		onArrowDown: v.handleArrowDown,
+		view:        v,
+	}
+
+	switch step {
+	case viewmodel.StepConfigOp:
+		v.renderOptions(inputView)
+		v.gui.Cursor = false
+	case viewmodel.StepConfigDiff:
+		inputView.Clear()
+		fmt.Fprint(inputView, v.viewModel.RenderDiff())
+		v.gui.Cursor = false
+	case viewmodel.StepConfigName:
+		v.prefill(inputView, v.viewModel.GetName())
+	case viewmodel.StepConfigValue:
+		v.prefill(inputView, v.viewModel.GetDefaultValue())
+	}
+
+	_, _ = v.gui.SetViewOnTop(editTopicConfigInput)
+
+	if _, err := v.gui.SetCurrentView(editTopicConfigInput); err != nil {
+		slog.Error("failed to set current view", "view", editTopicConfigInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *EditTopicConfigView) prefill(inputView *gocui.View, value string) {
+	v.gui.Cursor = true
+	if inputView.Buffer() == "" {
+		fmt.Fprint(inputView, value)
+		inputView.SetCursor(len(value), 0)
+	}
+}
+
+func (v *EditTopicConfigView) renderOptions(inputView *gocui.View) {
+	inputView.Clear()
+	selectedIdx := v.viewModel.GetSelectedOpIndex()
+	for i, option := range v.viewModel.GetOpOptions() {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
+func (v *EditTopicConfigView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *EditTopicConfigView) handleEnter() {
+	inputView, err := v.gui.View(editTopicConfigInput)
+	if err != nil {
+		return
+	}
+	value := strings.TrimSpace(inputView.Buffer())
+
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepConfigName:
+		v.viewModel.SetName(value)
+	case viewmodel.StepConfigValue:
+		v.viewModel.SetValue(value)
+	}
+
+	if v.viewModel.NextStep() {
+		if err := v.viewModel.Submit(); err != nil {
+			slog.Error("failed to alter topic config", "error", err)
+		}
+		return
+	}
+
+	inputView.Clear()
+	inputView.SetCursor(0, 0)
+	_ = v.render()
+}
+
+func (v *EditTopicConfigView) handleArrowUp() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepConfigOp {
+		return
+	}
+	v.viewModel.MoveOpUp()
+	if inputView, err := v.gui.View(editTopicConfigInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *EditTopicConfigView) handleArrowDown() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepConfigOp {
+		return
+	}
+	v.viewModel.MoveOpDown()
+	if inputView, err := v.gui.View(editTopicConfigInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *EditTopicConfigView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(editTopicConfigInput)
+	return nil
+}
@@ -0,0 +1,192 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const messageQueryInput = "message_query_input"
+
+type messageQueryEditor struct {
+	onEsc       func()
+	onEnter     func()
+	onArrowUp   func()
+	onArrowDown func()
+	view        *MessageQueryView
+}
+
+func (e *messageQueryEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp:
+		if e.onArrowUp != nil {
+			e.onArrowUp()
+		}
+		return
+	case gocui.KeyArrowDown:
+		if e.onArrowDown != nil {
+			e.onArrowDown()
+		}
+		return
+	}
+
+	// Prevent text input during start position selection
+	if e.view != nil && e.view.viewModel.GetCurrentStep() == viewmodel.StepMessageStart {
+		return
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type MessageQueryView struct {
+	viewModel *viewmodel.MessageQueryViewModel
+	gui       *gocui.Gui
+}
+
+func NewMessageQueryView(vm *viewmodel.MessageQueryViewModel) *MessageQueryView {
+	return &MessageQueryView{
+		viewModel: vm,
+	}
+}
+
+func (v *MessageQueryView) GetViewModel() *viewmodel.MessageQueryViewModel {
+	return v.viewModel
+}
+
+func (v *MessageQueryView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *MessageQueryView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	height := 2
+	if step == viewmodel.StepMessageStart {
+		height = len(v.viewModel.GetStartOptions()) + 1
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(messageQueryInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Editor = &messageQueryEditor{
+		onEsc:       v.handleEsc,
+		onEnter:     v.handleEnter,
+		onArrowUp:   v.handleArrowUp,
+		onArrowDown: v.handleArrowDown,
+		view:        v,
+	}
+
+	switch step {
+	case viewmodel.StepMessageStart:
+		v.renderOptions(inputView)
+		v.gui.Cursor = false
+	case viewmodel.StepMessageValue:
+		v.gui.Cursor = true
+		if value := v.viewModel.GetDefaultValue(); value != "" && inputView.Buffer() == "" {
+			fmt.Fprint(inputView, value)
+			inputView.SetCursor(len(value), 0)
+		}
+	default:
+		v.gui.Cursor = true
+	}
+
+	_, _ = v.gui.SetViewOnTop(messageQueryInput)
+
+	if _, err := v.gui.SetCurrentView(messageQueryInput); err != nil {
+		slog.Error("failed to set current view", "view", messageQueryInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *MessageQueryView) renderOptions(inputView *gocui.View) {
+	inputView.Clear()
+	selectedIdx := v.viewModel.GetSelectedStartIndex()
+	for i, option := range v.viewModel.GetStartOptions() {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
+func (v *MessageQueryView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *MessageQueryView) handleEnter() {
+	inputView, err := v.gui.View(messageQueryInput)
+	if err != nil {
+		return
+	}
+	value := strings.TrimSpace(inputView.Buffer())
+
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepMessageValue:
+		v.viewModel.SetValue(value)
+	case viewmodel.StepMessagePartition:
+		v.viewModel.SetPartition(value)
+	}
+
+	if v.viewModel.NextStep() {
+		if err := v.viewModel.Submit(); err != nil {
+			slog.Error("invalid message query", "error", err)
+			inputView.Title = " " + strings.ReplaceAll(err.Error(), "\n", ": ") + " "
+		}
+		return
+	}
+
+	inputView.Clear()
+	inputView.SetCursor(0, 0)
+	_ = v.render()
+}
+
+func (v *MessageQueryView) handleArrowUp() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepMessageStart {
+		return
+	}
+	v.viewModel.MoveStartUp()
+	if inputView, err := v.gui.View(messageQueryInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *MessageQueryView) handleArrowDown() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepMessageStart {
+		return
+	}
+	v.viewModel.MoveStartDown()
+	if inputView, err := v.gui.View(messageQueryInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *MessageQueryView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(messageQueryInput)
+	return nil
+}
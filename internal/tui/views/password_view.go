@@ -0,0 +1,106 @@
+package views
+
+import (
+	"log/slog"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const passwordInput = "password_input"
+
+type passwordEditor struct {
+	onEsc   func()
+	onEnter func()
+}
+
+func (e *passwordEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp, gocui.KeyArrowDown:
+		return
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type PasswordView struct {
+	viewModel *viewmodel.PasswordViewModel
+	gui       *gocui.Gui
+}
+
+func NewPasswordView(vm *viewmodel.PasswordViewModel) *PasswordView {
+	return &PasswordView{
+		viewModel: vm,
+	}
+}
+
+func (v *PasswordView) GetViewModel() *viewmodel.PasswordViewModel {
+	return v.viewModel
+}
+
+func (v *PasswordView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *PasswordView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	height := 2
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(passwordInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetTitle() + " "
+	inputView.Editable = true
+	inputView.Mask = '*'
+	inputView.Editor = &passwordEditor{
+		onEsc:   v.handleEsc,
+		onEnter: v.handleEnter,
+	}
+	v.gui.Cursor = true
+
+	_, _ = v.gui.SetViewOnTop(passwordInput)
+
+	if _, err := v.gui.SetCurrentView(passwordInput); err != nil {
+		slog.Error("failed to set current view", "view", passwordInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *PasswordView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *PasswordView) handleEnter() {
+	inputView, err := v.gui.View(passwordInput)
+	if err != nil {
+		return
+	}
+
+	if err := v.viewModel.Submit(inputView.Buffer()); err != nil {
+		inputView.Title = " " + v.viewModel.GetTitle() + " "
+	}
+}
+
+func (v *PasswordView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(passwordInput)
+	return nil
+}
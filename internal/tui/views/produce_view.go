@@ -0,0 +1,199 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const produceInput = "produce_input"
+
+type produceEditor struct {
+	onEsc       func()
+	onEnter     func()
+	onArrowUp   func()
+	onArrowDown func()
+	view        *ProduceView
+}
+
+func (e *produceEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp:
+		if e.onArrowUp != nil {
+			e.onArrowUp()
+		}
+		return
+	case gocui.KeyArrowDown:
+		if e.onArrowDown != nil {
+			e.onArrowDown()
+		}
+		return
+	}
+
+	// Prevent text input during source selection
+	if e.view != nil && e.view.viewModel.GetCurrentStep() == viewmodel.StepProduceSource {
+		return
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type ProduceView struct {
+	viewModel *viewmodel.ProduceViewModel
+	gui       *gocui.Gui
+}
+
+func NewProduceView(vm *viewmodel.ProduceViewModel) *ProduceView {
+	return &ProduceView{
+		viewModel: vm,
+	}
+}
+
+func (v *ProduceView) GetViewModel() *viewmodel.ProduceViewModel {
+	return v.viewModel
+}
+
+func (v *ProduceView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *ProduceView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	height := 2
+	if step == viewmodel.StepProduceSource {
+		height = len(v.viewModel.GetSourceOptions()) + 1
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(produceInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Editor = &produceEditor{
+		onEsc:       v.handleEsc,
+		onEnter:     v.handleEnter,
+		onArrowUp:   v.handleArrowUp,
+		onArrowDown: v.handleArrowDown,
+		view:        v,
+	}
+
+	if step == viewmodel.StepProduceSource {
+		v.renderOptions(inputView)
+		v.gui.Cursor = false
+	} else {
+		v.gui.Cursor = true
+	}
+
+	_, _ = v.gui.SetViewOnTop(produceInput)
+
+	if _, err := v.gui.SetCurrentView(produceInput); err != nil {
+		slog.Error("failed to set current view", "view", produceInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *ProduceView) renderOptions(inputView *gocui.View) {
+	inputView.Clear()
+	selectedIdx := v.viewModel.GetSelectedSourceIndex()
+	for i, option := range v.viewModel.GetSourceOptions() {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
+func (v *ProduceView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *ProduceView) handleEnter() {
+	inputView, err := v.gui.View(produceInput)
+	if err != nil {
+		return
+	}
+	v.saveCurrentValue(inputView)
+
+	if v.viewModel.NextStep() {
+		if err := v.viewModel.Submit(); err != nil {
+			slog.Error("invalid produce input", "error", err)
+			inputView.Title = " " + strings.ReplaceAll(err.Error(), "\n", ": ") + " "
+		}
+		return
+	}
+
+	inputView.Clear()
+	inputView.SetCursor(0, 0)
+	_ = v.render()
+}
+
+// saveCurrentValue keeps key, value and separator untrimmed since surrounding
+// whitespace may be meaningful there
+func (v *ProduceView) saveCurrentValue(inputView *gocui.View) {
+	value := strings.TrimRight(inputView.Buffer(), "\n")
+
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepProduceKey:
+		v.viewModel.SetKey(value)
+	case viewmodel.StepProduceValue:
+		v.viewModel.SetValue(value)
+	case viewmodel.StepProduceFile:
+		v.viewModel.SetFilePath(strings.TrimSpace(value))
+	case viewmodel.StepProduceKeySeparator:
+		v.viewModel.SetKeySeparator(value)
+	case viewmodel.StepProduceHeaders:
+		v.viewModel.SetHeaders(strings.TrimSpace(value))
+	case viewmodel.StepProducePartition:
+		v.viewModel.SetPartition(strings.TrimSpace(value))
+	}
+}
+
+func (v *ProduceView) handleArrowUp() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepProduceSource {
+		return
+	}
+	v.viewModel.MoveSourceUp()
+	if inputView, err := v.gui.View(produceInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *ProduceView) handleArrowDown() {
+	if v.viewModel.GetCurrentStep() != viewmodel.StepProduceSource {
+		return
+	}
+	v.viewModel.MoveSourceDown()
+	if inputView, err := v.gui.View(produceInput); err == nil {
+		v.renderOptions(inputView)
+	}
+}
+
+func (v *ProduceView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(produceInput)
+	return nil
+}
@@ -0,0 +1,234 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const registerSchemaInput = "register_schema_input"
+
+type registerSchemaEditor struct {
+	onEsc       func()
+	onEnter     func()
+	onArrowUp   func()
+	onArrowDown func()
+	view        *RegisterSchemaView
+}
+
+func (e *registerSchemaEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp:
+		if e.onArrowUp != nil {
+			e.onArrowUp()
+		}
+		return
+	case gocui.KeyArrowDown:
+		if e.onArrowDown != nil {
+			e.onArrowDown()
+		}
+		return
+	}
+
+	// Prevent text input during list selection and result steps
+	if e.view != nil {
+		switch e.view.viewModel.GetCurrentStep() {
+		case viewmodel.StepRegisterType, viewmodel.StepRegisterSource, viewmodel.StepRegisterMode, viewmodel.StepRegisterResult:
+			return
+		}
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type RegisterSchemaView struct {
+	viewModel *viewmodel.RegisterSchemaViewModel
+	gui       *gocui.Gui
+}
+
+func NewRegisterSchemaView(vm *viewmodel.RegisterSchemaViewModel) *RegisterSchemaView {
+	return &RegisterSchemaView{
+		viewModel: vm,
+	}
+}
+
+func (v *RegisterSchemaView) GetViewModel() *viewmodel.RegisterSchemaViewModel {
+	return v.viewModel
+}
+
+func (v *RegisterSchemaView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *RegisterSchemaView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	var height int
+	switch step {
+	case viewmodel.StepRegisterType:
+		height = len(v.viewModel.GetTypeOptions()) + 1
+	case viewmodel.StepRegisterSource:
+		height = len(v.viewModel.GetSourceOptions()) + 1
+	case viewmodel.StepRegisterMode:
+		height = len(v.viewModel.GetModeOptions()) + 1
+	case viewmodel.StepRegisterResult:
+		height = min(v.viewModel.GetResultLineCount()+1, maxY-4)
+	default:
+		height = 2
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(registerSchemaInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Wrap = step == viewmodel.StepRegisterResult
+	inputView.Editor = &registerSchemaEditor{
+		onEsc:       v.handleEsc,
+		onEnter:     v.handleEnter,
+		onArrowUp:   v.handleArrowUp,
+		onArrowDown: v.handleArrowDown,
+		view:        v,
+	}
+
+	switch step {
+	case viewmodel.StepRegisterType:
+		v.renderList(inputView, v.viewModel.GetTypeOptions(), v.viewModel.GetSelectedTypeIndex())
+		v.gui.Cursor = false
+	case viewmodel.StepRegisterSource:
+		v.renderList(inputView, v.viewModel.GetSourceOptions(), v.viewModel.GetSelectedSourceIndex())
+		v.gui.Cursor = false
+	case viewmodel.StepRegisterMode:
+		v.renderList(inputView, v.viewModel.GetModeOptions(), v.viewModel.GetSelectedModeIndex())
+		v.gui.Cursor = false
+	case viewmodel.StepRegisterResult:
+		inputView.Clear()
+		fmt.Fprint(inputView, v.viewModel.RenderResult())
+		v.gui.Cursor = false
+	case viewmodel.StepRegisterSubject:
+		v.gui.Cursor = true
+		if inputView.Buffer() == "" {
+			subject := v.viewModel.GetSubject()
+			fmt.Fprint(inputView, subject)
+			inputView.SetCursor(len(subject), 0)
+		}
+	default:
+		inputView.SetCursor(0, 0)
+		v.gui.Cursor = true
+	}
+
+	_, _ = v.gui.SetViewOnTop(registerSchemaInput)
+
+	if _, err := v.gui.SetCurrentView(registerSchemaInput); err != nil {
+		slog.Error("failed to set current view", "view", registerSchemaInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *RegisterSchemaView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *RegisterSchemaView) handleEnter() {
+	v.saveCurrentValue()
+
+	if v.viewModel.NextStep() {
+		if err := v.viewModel.Submit(); err != nil {
+			slog.Error("failed to register schema", "error", err)
+		}
+	} else {
+		v.clearAndRender()
+	}
+}
+
+func (v *RegisterSchemaView) handleArrowUp() {
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepRegisterType:
+		v.viewModel.MoveTypeUp()
+	case viewmodel.StepRegisterSource:
+		v.viewModel.ToggleSource()
+	case viewmodel.StepRegisterMode:
+		v.viewModel.ToggleMode()
+	default:
+		return
+	}
+	v.clearAndRender()
+}
+
+func (v *RegisterSchemaView) handleArrowDown() {
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepRegisterType:
+		v.viewModel.MoveTypeDown()
+	case viewmodel.StepRegisterSource:
+		v.viewModel.ToggleSource()
+	case viewmodel.StepRegisterMode:
+		v.viewModel.ToggleMode()
+	default:
+		return
+	}
+	v.clearAndRender()
+}
+
+func (v *RegisterSchemaView) renderList(inputView *gocui.View, options []string, selectedIdx int) {
+	inputView.Clear()
+	for i, option := range options {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
+func (v *RegisterSchemaView) saveCurrentValue() {
+	inputView, err := v.gui.View(registerSchemaInput)
+	if err != nil {
+		return
+	}
+	value := strings.TrimSpace(inputView.Buffer())
+
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepRegisterSubject:
+		v.viewModel.SetSubject(value)
+	case viewmodel.StepRegisterFile:
+		v.viewModel.SetFilePath(value)
+	}
+}
+
+func (v *RegisterSchemaView) clearAndRender() {
+	inputView, err := v.gui.View(registerSchemaInput)
+	if err != nil {
+		return
+	}
+	inputView.Clear()
+	inputView.SetCursor(0, 0)
+	_ = v.render()
+}
+
+func (v *RegisterSchemaView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(registerSchemaInput)
+	return nil
+}
@@ -0,0 +1,231 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const resetOffsetsInput = "reset_offsets_input"
+
+type resetOffsetsEditor struct {
+	onEsc       func()
+	onEnter     func()
+	onArrowUp   func()
+	onArrowDown func()
+	view        *ResetOffsetsView
+}
+
+func (e *resetOffsetsEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp:
+		if e.onArrowUp != nil {
+			e.onArrowUp()
+		}
+		return
+	case gocui.KeyArrowDown:
+		if e.onArrowDown != nil {
+			e.onArrowDown()
+		}
+		return
+	}
+
+	// Prevent text input during list selection and preview steps
+	if e.view != nil {
+		switch e.view.viewModel.GetCurrentStep() {
+		case viewmodel.StepResetScope, viewmodel.StepResetStrategy, viewmodel.StepResetPreview:
+			return
+		}
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type ResetOffsetsView struct {
+	viewModel *viewmodel.ResetOffsetsViewModel
+	gui       *gocui.Gui
+}
+
+func NewResetOffsetsView(vm *viewmodel.ResetOffsetsViewModel) *ResetOffsetsView {
+	return &ResetOffsetsView{
+		viewModel: vm,
+	}
+}
+
+func (v *ResetOffsetsView) GetViewModel() *viewmodel.ResetOffsetsViewModel {
+	return v.viewModel
+}
+
+func (v *ResetOffsetsView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *ResetOffsetsView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	var height int
+	switch step {
+	case viewmodel.StepResetScope:
+		height = len(v.viewModel.GetScopeOptions()) + 1
+	case viewmodel.StepResetStrategy:
+		height = len(v.viewModel.GetStrategyOptions()) + 1
+	case viewmodel.StepResetPreview:
+		height = min(v.viewModel.GetPreviewLineCount()+1, maxY-4)
+	default:
+		height = 2
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(resetOffsetsInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Editor = &resetOffsetsEditor{
+		onEsc:       v.handleEsc,
+		onEnter:     v.handleEnter,
+		onArrowUp:   v.handleArrowUp,
+		onArrowDown: v.handleArrowDown,
+		view:        v,
+	}
+
+	switch step {
+	case viewmodel.StepResetScope:
+		v.renderList(inputView, v.viewModel.GetScopeOptions(), v.viewModel.GetSelectedScopeIndex())
+		v.gui.Cursor = false
+	case viewmodel.StepResetStrategy:
+		v.renderList(inputView, v.viewModel.GetStrategyOptions(), v.viewModel.GetSelectedStrategyIndex())
+		v.gui.Cursor = false
+	case viewmodel.StepResetPreview:
+		inputView.Clear()
+		fmt.Fprint(inputView, v.viewModel.RenderPreview())
+		v.gui.Cursor = false
+	case viewmodel.StepResetTopic:
+		v.prefill(inputView, v.viewModel.GetTopic())
+	case viewmodel.StepResetPartition:
+		v.prefill(inputView, v.viewModel.GetPartition())
+	default:
+		inputView.SetCursor(0, 0)
+		v.gui.Cursor = true
+	}
+
+	_, _ = v.gui.SetViewOnTop(resetOffsetsInput)
+
+	if _, err := v.gui.SetCurrentView(resetOffsetsInput); err != nil {
+		slog.Error("failed to set current view", "view", resetOffsetsInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *ResetOffsetsView) prefill(inputView *gocui.View, value string) {
+	v.gui.Cursor = true
+	if inputView.Buffer() == "" {
+		fmt.Fprint(inputView, value)
+		inputView.SetCursor(len(value), 0)
+	}
+}
+
+func (v *ResetOffsetsView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *ResetOffsetsView) handleEnter() {
+	v.saveCurrentValue()
+
+	if v.viewModel.NextStep() {
+		if err := v.viewModel.Submit(); err != nil {
+			slog.Error("failed to reset offsets", "error", err)
+		}
+	} else {
+		v.clearAndRender()
+	}
+}
+
+func (v *ResetOffsetsView) handleArrowUp() {
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepResetScope:
+		v.viewModel.MoveScopeUp()
+	case viewmodel.StepResetStrategy:
+		v.viewModel.MoveStrategyUp()
+	default:
+		return
+	}
+	v.clearAndRender()
+}
+
+func (v *ResetOffsetsView) handleArrowDown() {
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepResetScope:
+		v.viewModel.MoveScopeDown()
+	case viewmodel.StepResetStrategy:
+		v.viewModel.MoveStrategyDown()
+	default:
+		return
+	}
+	v.clearAndRender()
+}
+
+func (v *ResetOffsetsView) renderList(inputView *gocui.View, options []string, selectedIdx int) {
+	inputView.Clear()
+	for i, option := range options {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
+func (v *ResetOffsetsView) saveCurrentValue() {
+	inputView, err := v.gui.View(resetOffsetsInput)
+	if err != nil {
+		return
+	}
+	value := strings.TrimSpace(inputView.Buffer())
+
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepResetTopic:
+		v.viewModel.SetTopic(value)
+	case viewmodel.StepResetPartition:
+		v.viewModel.SetPartition(value)
+	case viewmodel.StepResetValue:
+		v.viewModel.SetValue(value)
+	}
+}
+
+func (v *ResetOffsetsView) clearAndRender() {
+	inputView, err := v.gui.View(resetOffsetsInput)
+	if err != nil {
+		return
+	}
+	inputView.Clear()
+	inputView.SetCursor(0, 0)
+	_ = v.render()
+}
+
+func (v *ResetOffsetsView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(resetOffsetsInput)
+	return nil
+}
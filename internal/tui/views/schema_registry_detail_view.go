@@ -41,12 +41,18 @@ func (v *SchemaRegistryDetailView) Render(g *gocui.Gui, gocuiView *gocui.View) e
 	gocuiView.Clear()
 	gocuiView.Title = v.viewModel.GetTitle()
 
-	schemaType := v.viewModel.GetSchemaType()
-	if schemaType != "" {
-		fmt.Fprintf(gocuiView, "Type: %s\n\n", schemaType)
-	}
+	maxX, _ := gocuiView.Size()
+	fmt.Fprint(gocuiView, v.viewModel.RenderTabs())
 
-	content := v.viewModel.GetSchemaContent()
+	var content string
+	switch v.viewModel.GetActiveTab() {
+	case viewmodel.SchemaTabVersions:
+		content = v.viewModel.RenderVersionsTable(maxX)
+	case viewmodel.SchemaTabDiff:
+		content = v.viewModel.RenderDiff(maxX)
+	default:
+		content = v.viewModel.RenderSchema()
+	}
 	fmt.Fprint(gocuiView, content)
 
 	return nil
@@ -41,9 +41,17 @@ func (v *TopicDetailView) Render(g *gocui.Gui, gocuiView *gocui.View) error {
 	gocuiView.Clear()
 	gocuiView.Title = v.viewModel.GetTitle()
 
-	maxX, _ := gocuiView.Size()
-	content := v.viewModel.RenderPartitionsTable(maxX)
-	fmt.Fprint(gocuiView, content)
+	maxX, maxY := gocuiView.Size()
+	fmt.Fprint(gocuiView, v.viewModel.RenderTabs())
+
+	switch v.viewModel.GetActiveTab() {
+	case viewmodel.TabConfiguration:
+		fmt.Fprint(gocuiView, v.viewModel.RenderConfigTable(maxX))
+	case viewmodel.TabMessages:
+		fmt.Fprint(gocuiView, v.viewModel.RenderMessagesTable(maxX, maxY))
+	default:
+		fmt.Fprint(gocuiView, v.viewModel.RenderPartitionsTable(maxX))
+	}
 
 	return nil
 }
@@ -0,0 +1,224 @@
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	viewmodel "github.com/jurabek/lazykafka/internal/tui/view_models"
+)
+
+const truncateInput = "truncate_input"
+
+type truncateEditor struct {
+	onEsc       func()
+	onEnter     func()
+	onArrowUp   func()
+	onArrowDown func()
+	view        *TruncateView
+}
+
+func (e *truncateEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	switch key {
+	case gocui.KeyEsc:
+		if e.onEsc != nil {
+			e.onEsc()
+		}
+		return
+	case gocui.KeyEnter:
+		if e.onEnter != nil {
+			e.onEnter()
+		}
+		return
+	case gocui.KeyArrowUp:
+		if e.onArrowUp != nil {
+			e.onArrowUp()
+		}
+		return
+	case gocui.KeyArrowDown:
+		if e.onArrowDown != nil {
+			e.onArrowDown()
+		}
+		return
+	}
+
+	// Prevent text input during list selection and preview steps
+	if e.view != nil {
+		switch e.view.viewModel.GetCurrentStep() {
+		case viewmodel.StepTruncateScope, viewmodel.StepTruncateTarget, viewmodel.StepTruncatePreview:
+			return
+		}
+	}
+
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+}
+
+type TruncateView struct {
+	viewModel *viewmodel.TruncateViewModel
+	gui       *gocui.Gui
+}
+
+func NewTruncateView(vm *viewmodel.TruncateViewModel) *TruncateView {
+	return &TruncateView{
+		viewModel: vm,
+	}
+}
+
+func (v *TruncateView) GetViewModel() *viewmodel.TruncateViewModel {
+	return v.viewModel
+}
+
+func (v *TruncateView) Initialize(g *gocui.Gui) error {
+	v.gui = g
+	return v.render()
+}
+
+func (v *TruncateView) render() error {
+	maxX, maxY := v.gui.Size()
+
+	step := v.viewModel.GetCurrentStep()
+	var height int
+	switch step {
+	case viewmodel.StepTruncateScope:
+		height = len(v.viewModel.GetScopeOptions()) + 1
+	case viewmodel.StepTruncateTarget:
+		height = len(v.viewModel.GetTargetOptions()) + 1
+	case viewmodel.StepTruncatePreview:
+		height = min(v.viewModel.GetPreviewLineCount()+1, maxY-4)
+	default:
+		height = 2
+	}
+
+	x0 := (maxX - wizardWidth) / 2
+	y0 := (maxY - height) / 2
+	x1 := x0 + wizardWidth
+	y1 := y0 + height
+
+	inputView, err := v.gui.SetView(truncateInput, x0, y0, x1, y1)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	inputView.Title = " " + v.viewModel.GetStepTitle() + " "
+	inputView.Editable = true
+	inputView.Editor = &truncateEditor{
+		onEsc:       v.handleEsc,
+		onEnter:     v.handleEnter,
+		onArrowUp:   v.handleArrowUp,
+		onArrowDown: v.handleArrowDown,
+		view:        v,
+	}
+
+	switch step {
+	case viewmodel.StepTruncateScope:
+		v.renderList(inputView, v.viewModel.GetScopeOptions(), v.viewModel.GetSelectedScopeIndex())
+		v.gui.Cursor = false
+	case viewmodel.StepTruncateTarget:
+		v.renderList(inputView, v.viewModel.GetTargetOptions(), v.viewModel.GetSelectedTargetIndex())
+		v.gui.Cursor = false
+	case viewmodel.StepTruncatePreview:
+		inputView.Clear()
+		fmt.Fprint(inputView, v.viewModel.RenderPreview())
+		v.gui.Cursor = false
+	case viewmodel.StepTruncatePartition:
+		v.gui.Cursor = true
+		if inputView.Buffer() == "" {
+			partition := v.viewModel.GetPartition()
+			fmt.Fprint(inputView, partition)
+			inputView.SetCursor(len(partition), 0)
+		}
+	default:
+		inputView.SetCursor(0, 0)
+		v.gui.Cursor = true
+	}
+
+	_, _ = v.gui.SetViewOnTop(truncateInput)
+
+	if _, err := v.gui.SetCurrentView(truncateInput); err != nil {
+		slog.Error("failed to set current view", "view", truncateInput, "error", err)
+	}
+
+	return nil
+}
+
+func (v *TruncateView) handleEsc() {
+	v.viewModel.Cancel()
+}
+
+func (v *TruncateView) handleEnter() {
+	v.saveCurrentValue()
+
+	if v.viewModel.NextStep() {
+		if err := v.viewModel.Submit(); err != nil {
+			slog.Error("failed to truncate topic", "error", err)
+		}
+	} else {
+		v.clearAndRender()
+	}
+}
+
+func (v *TruncateView) handleArrowUp() {
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepTruncateScope:
+		v.viewModel.ToggleScope()
+	case viewmodel.StepTruncateTarget:
+		v.viewModel.MoveTargetUp()
+	default:
+		return
+	}
+	v.clearAndRender()
+}
+
+func (v *TruncateView) handleArrowDown() {
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepTruncateScope:
+		v.viewModel.ToggleScope()
+	case viewmodel.StepTruncateTarget:
+		v.viewModel.MoveTargetDown()
+	default:
+		return
+	}
+	v.clearAndRender()
+}
+
+func (v *TruncateView) renderList(inputView *gocui.View, options []string, selectedIdx int) {
+	inputView.Clear()
+	for i, option := range options {
+		prefix := "  "
+		if i == selectedIdx {
+			prefix = "> "
+		}
+		fmt.Fprintf(inputView, "%s%s\n", prefix, option)
+	}
+}
+
+func (v *TruncateView) saveCurrentValue() {
+	inputView, err := v.gui.View(truncateInput)
+	if err != nil {
+		return
+	}
+	value := strings.TrimSpace(inputView.Buffer())
+
+	switch v.viewModel.GetCurrentStep() {
+	case viewmodel.StepTruncatePartition:
+		v.viewModel.SetPartition(value)
+	case viewmodel.StepTruncateValue:
+		v.viewModel.SetValue(value)
+	}
+}
+
+func (v *TruncateView) clearAndRender() {
+	inputView, err := v.gui.View(truncateInput)
+	if err != nil {
+		return
+	}
+	inputView.Clear()
+	inputView.SetCursor(0, 0)
+	_ = v.render()
+}
+
+func (v *TruncateView) Destroy(g *gocui.Gui) error {
+	g.Cursor = false
+	_ = g.DeleteView(truncateInput)
+	return nil
+}